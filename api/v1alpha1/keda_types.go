@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/kyma-project/keda-manager/pkg/reconciler/api"
 	corev1 "k8s.io/api/core/v1"
@@ -43,6 +44,9 @@ const (
 
 	ConditionReasonDeploymentUpdateErr      = ConditionReason("KedaDeploymentUpdateErr")
 	ConditionReasonNetworkPolicyUpdateErr   = ConditionReason("NetworkPolicyUpdateErr")
+	ConditionReasonPDBUpdateErr             = ConditionReason("PodDisruptionBudgetUpdateErr")
+	ConditionReasonExternalScalerErr        = ConditionReason("ExternalScalerErr")
+	ConditionReasonWatchNamespacesErr       = ConditionReason("WatchNamespacesErr")
 	ConditionReasonVerificationErr          = ConditionReason("VerificationErr")
 	ConditionReasonVerified                 = ConditionReason("Verified")
 	ConditionReasonDeploymentReplicaFailure = ConditionReason("DeploymentReplicaFailure")
@@ -51,13 +55,33 @@ const (
 	ConditionReasonValidationErr            = ConditionReason("ValidationErr")
 	ConditionReasonInitialized              = ConditionReason("Initialized")
 	ConditionReasonKedaDuplicated           = ConditionReason("KedaDuplicated")
+	ConditionReasonServedConflict           = ConditionReason("ServedConflict")
 	ConditionReasonDeletion                 = ConditionReason("Deletion")
 	ConditionReasonDeletionErr              = ConditionReason("DeletionErr")
 	ConditionReasonDeleted                  = ConditionReason("Deleted")
 
-	ConditionTypeDeploymentFailure = ConditionType("DeploymentFailure")
-	ConditionTypeInstalled         = ConditionType("Installed")
-	ConditionTypeDeleted           = ConditionType("Deleted")
+	ConditionReasonAPIServiceAvailable      = ConditionReason("APIServiceAvailable")
+	ConditionReasonAPIServiceUnavailable    = ConditionReason("APIServiceUnavailable")
+	ConditionReasonWebhookEndpointsReady    = ConditionReason("WebhookEndpointsReady")
+	ConditionReasonWebhookEndpointsNotReady = ConditionReason("WebhookEndpointsNotReady")
+	ConditionReasonPlanned                  = ConditionReason("Planned")
+	ConditionReasonPlanErr                  = ConditionReason("PlanErr")
+	ConditionReasonReconcilePaused          = ConditionReason("ReconcilePaused")
+	ConditionReasonPruneErr                 = ConditionReason("PruneErr")
+	ConditionReasonBackupErr                = ConditionReason("BackupErr")
+	ConditionReasonRestoreErr               = ConditionReason("RestoreErr")
+	ConditionReasonRestored                 = ConditionReason("Restored")
+
+	ConditionTypeDeploymentFailure   = ConditionType("DeploymentFailure")
+	ConditionTypeInstalled           = ConditionType("Installed")
+	ConditionTypeDeleted             = ConditionType("Deleted")
+	ConditionTypeMetricsAPIAvailable = ConditionType("MetricsAPIAvailable")
+	ConditionTypeWebhookReachable    = ConditionType("WebhookReachable")
+	ConditionTypePlanned             = ConditionType("Planned")
+	ConditionTypePaused              = ConditionType("Paused")
+	ConditionTypeRestored            = ConditionType("Restored")
+	// ConditionTypeAddonInstalled reports the installation of the HTTP add-on
+	ConditionTypeAddonInstalled = ConditionType("AddonInstalled")
 
 	CommonLogLevelDebug = LogLevel("debug")
 	CommonLogLevelInfo  = LogLevel("info")
@@ -83,23 +107,59 @@ const (
 	zapEncoder      = "--zap-encoder"
 	zapTimeEncoding = "--zap-time-encoding"
 
-	conditionTypeAddon = "AddonInstalled"
-
-	ConditionReasonAddonInstalled  = "HTTPAddonInstalled"
-	ConditionReasonAddonDeleted    = "HTTPAddonDeleted"
-	ConditionReasonAddonInstallErr = "HTTPAddonInstallErr"
-	ConditionReasonAddonDisabled   = "HTTPAddonDisabled"
-	ConditionReasonAddonInUse      = "HTTPAddonInUse"
-
-	AnnotationAddonEnabled        = "keda.kyma-project.io/addon-enabled"
-	AnnotationAddonNamespace      = "keda.kyma-project.io/addon-namespace"
+	// the add-on condition reasons are prefixed with the reason prefix of the add-on, e.g. HTTPAddonInstalled
+	AddonReasonInstalled  = "AddonInstalled"
+	AddonReasonDeleted    = "AddonDeleted"
+	AddonReasonInstallErr = "AddonInstallErr"
+	AddonReasonDisabled   = "AddonDisabled"
+	AddonReasonInUse      = "AddonInUse"
+	AddonReasonDigestErr  = "AddonDigestErr"
+	// AddonReasonVerification is set while the add-on components are not ready yet
+	AddonReasonVerification = "AddonVerification"
+	// AddonReasonUpgradeFailed is set when the upgraded add-on was not ready in time and the previous installation was restored
+	AddonReasonUpgradeFailed = "AddonUpgradeFailed"
+
+	HTTPAddonReasonPrefix = "HTTP"
+
+	ConditionReasonAddonInstalled     = HTTPAddonReasonPrefix + AddonReasonInstalled
+	ConditionReasonAddonDeleted       = HTTPAddonReasonPrefix + AddonReasonDeleted
+	ConditionReasonAddonInstallErr    = HTTPAddonReasonPrefix + AddonReasonInstallErr
+	ConditionReasonAddonDisabled      = HTTPAddonReasonPrefix + AddonReasonDisabled
+	ConditionReasonAddonInUse         = HTTPAddonReasonPrefix + AddonReasonInUse
+	ConditionReasonAddonDigestErr     = HTTPAddonReasonPrefix + AddonReasonDigestErr
+	ConditionReasonAddonVerification  = HTTPAddonReasonPrefix + AddonReasonVerification
+	ConditionReasonAddonUpgradeFailed = HTTPAddonReasonPrefix + AddonReasonUpgradeFailed
+
+	// Deprecated: use spec.httpAddon.enabled
+	AnnotationAddonEnabled = "keda.kyma-project.io/addon-enabled"
+	// Deprecated: use spec.httpAddon.namespace
+	AnnotationAddonNamespace = "keda.kyma-project.io/addon-namespace"
+	// Deprecated: use spec.httpAddon.istio.enabledSidecarInjection
 	AnnotationAddonIstioInjection = "keda.kyma-project.io/addon-istio-injection"
 
-	AnnotationAddonInstalledVersion   = "keda.kyma-project.io/addon-installed-version"
+	AnnotationReconcilePaused = "keda.kyma-project.io/reconcile-paused"
+
+	// Deprecated: the installed HTTP add-on version is recorded in status.addons
+	AnnotationAddonInstalledVersion = "keda.kyma-project.io/addon-installed-version"
+	// Deprecated: the installed HTTP add-on namespace is recorded in status.addons
 	AnnotationAddonInstalledNamespace = "keda.kyma-project.io/addon-installed-namespace"
 
+	DefaultPriorityClassName = "keda-priority-class"
+
+	ReconcilePolicyApply = ReconcilePolicy("Apply")
+	ReconcilePolicyPlan  = ReconcilePolicy("Plan")
+
+	DeletionPolicySafe     = DeletionPolicy("Safe")
+	DeletionPolicyCascade  = DeletionPolicy("Cascade")
+	DeletionPolicyKeepCRDs = DeletionPolicy("KeepCRDs")
+
+	PlanActionCreate = "Create"
+	PlanActionUpdate = "Update"
+	PlanActionDelete = "Delete"
+
 	DefaultAddonNamespace = "kyma-system"
 	DefaultAddonVersion   = "0.15.0"
+	AddonVersionLatest    = "latest"
 )
 
 // +kubebuilder:validation:Enum=debug;info;error
@@ -200,6 +260,29 @@ func (o *LoggingCommonCfg) AppendMissingArgs(existingArgs []string) []string {
 	return missingArgs
 }
 
+// LoggingCommonCfgFromArgs returns the logging configuration set by the zap flags in the container args
+func LoggingCommonCfgFromArgs(args []string) *LoggingCommonCfg {
+	cfg := &LoggingCommonCfg{}
+	for _, arg := range args {
+		flag, value, found := strings.Cut(arg, "=")
+		if !found {
+			continue
+		}
+		switch flag {
+		case zapLogLevel:
+			level := LogLevel(value)
+			cfg.Level = &level
+		case zapEncoder:
+			format := LogFormat(value)
+			cfg.Format = &format
+		case zapTimeEncoding:
+			encoding := LogTimeEncoding(value)
+			cfg.TimeEncoding = &encoding
+		}
+	}
+	return cfg
+}
+
 // Sanitize converts "text" to "console" for the Format field since zap only accepts "console" or "json"
 func (o *LoggingCommonCfg) Sanitize() {
 	if o.Format != nil && *o.Format == "text" {
@@ -234,13 +317,170 @@ type PodAnnotations struct {
 	AdmissionWebhook map[string]string `json:"admissionWebhook,omitempty"`
 }
 
+type Replicas struct {
+	// +kubebuilder:validation:Minimum=1
+	Operator *int32 `json:"operator,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MetricsServer *int32 `json:"metricServer,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	AdmissionWebhook *int32 `json:"admissionWebhook,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetCfg struct {
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// PodDisruptionBudgets configures the PodDisruptionBudgets managed by keda-manager.
+// A PodDisruptionBudget is created only for components with a non-empty configuration.
+type PodDisruptionBudgets struct {
+	Operator         *PodDisruptionBudgetCfg `json:"operator,omitempty"`
+	MetricsServer    *PodDisruptionBudgetCfg `json:"metricServer,omitempty"`
+	AdmissionWebhook *PodDisruptionBudgetCfg `json:"admissionWebhook,omitempty"`
+}
+
+type SchedulingCfg struct {
+	NodeSelector              map[string]string                 `json:"nodeSelector,omitempty"`
+	Tolerations               []corev1.Toleration               `json:"tolerations,omitempty"`
+	Affinity                  *corev1.Affinity                  `json:"affinity,omitempty"`
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+type Scheduling struct {
+	Operator         *SchedulingCfg `json:"operator,omitempty"`
+	MetricsServer    *SchedulingCfg `json:"metricServer,omitempty"`
+	AdmissionWebhook *SchedulingCfg `json:"admissionWebhook,omitempty"`
+}
+
+// +kubebuilder:validation:MaxLength=253
+// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+type PriorityClassName string
+
+type PriorityClassNames struct {
+	Operator         PriorityClassName `json:"operator,omitempty"`
+	MetricsServer    PriorityClassName `json:"metricServer,omitempty"`
+	AdmissionWebhook PriorityClassName `json:"admissionWebhook,omitempty"`
+}
+
+// ReconcilePolicy defines if the changes are applied on the cluster or only planned with a server-side dry-run
+// +kubebuilder:validation:Enum=Apply;Plan
+type ReconcilePolicy string
+
+// DeletionPolicy defines how KEDA resources are removed when the Keda CR is deleted:
+// Safe blocks the deletion while KEDA custom resources exist, Cascade removes them together with the CRDs
+// and KeepCRDs removes KEDA but keeps the CRDs and the custom resources on the cluster
+// +kubebuilder:validation:Enum=Safe;Cascade;KeepCRDs
+type DeletionPolicy string
+
+// AddonVersion is a semantic version of the KEDA HTTP add-on or "latest"
+// +kubebuilder:validation:Pattern=`^(latest|[vV]?[0-9]+\.[0-9]+\.[0-9]+.*)$`
+type AddonVersion string
+
+type HTTPAddon struct {
+	// Enabled installs the KEDA HTTP add-on, the add-on is disabled by default
+	Enabled *bool `json:"enabled,omitempty"`
+	// Namespace the add-on is installed in, kyma-system is used by default
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string    `json:"namespace,omitempty"`
+	Istio     *IstioCfg `json:"istio,omitempty"`
+	// Version of the KEDA HTTP add-on, "latest" resolves the newest release; the version bundled with keda-manager is used by default
+	Version AddonVersion `json:"version,omitempty"`
+	// ManifestsConfigMap is the name of a ConfigMap in the Keda CR namespace with the add-on CRDs and manifest
+	// of the configured version; the manifests bundled with keda-manager or downloaded from GitHub are used by default
+	ManifestsConfigMap string `json:"manifestsConfigMap,omitempty"`
+	// ManifestDigests overrides the SHA-256 digests of the add-on release assets pinned in keda-manager
+	ManifestDigests *AddonManifestDigests `json:"manifestDigests,omitempty"`
+	Interceptor     *AddonComponentCfg    `json:"interceptor,omitempty"`
+	Scaler          *AddonComponentCfg    `json:"scaler,omitempty"`
+	Operator        *AddonComponentCfg    `json:"operator,omitempty"`
+}
+
+// AddonComponentCfg overrides the settings of a single HTTP add-on component from the upstream release
+type AddonComponentCfg struct {
+	// +kubebuilder:validation:Minimum=1
+	Replicas  *int32                       `json:"replicas,omitempty"`
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	LogLevel  *LogLevel                    `json:"logLevel,omitempty"`
+}
+
+// +kubebuilder:validation:Pattern=`^(sha256:)?[a-f0-9]{64}$`
+type ManifestDigest string
+
+type AddonManifestDigests struct {
+	// CRDs is the digest of the keda-add-ons-http-<version>-crds.yaml release asset
+	CRDs ManifestDigest `json:"crds,omitempty"`
+	// Manifest is the digest of the keda-add-ons-http-<version>.yaml release asset
+	Manifest ManifestDigest `json:"manifest,omitempty"`
+}
+
+// ExternalScaler is a gRPC external scaler deployed by keda-manager in the kyma-system namespace;
+// keda-operator reaches it at keda-external-scaler-<name>.kyma-system.svc.cluster.local:<port>
+type ExternalScaler struct {
+	// Name of the scaler, used in the names of the scaler resources
+	// +kubebuilder:validation:MaxLength=42
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Image of the scaler gRPC server
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Port the scaler gRPC server listens on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=9090
+	Port      int32                        `json:"port,omitempty"`
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// TLSSecret is the name of a Secret in the kyma-system namespace with the scaler server certificates,
+	// the Secret is mounted read-only in the scaler container at /etc/keda-external-scaler/certs
+	TLSSecret string `json:"tlsSecret,omitempty"`
+}
+
 // KedaSpec defines the desired state of Keda
 type KedaSpec struct {
-	Istio          *Istio          `json:"istio,omitempty"`
-	Logging        *LoggingCfg     `json:"logging,omitempty"`
-	Resources      *Resources      `json:"resources,omitempty"`
-	Env            EnvVars         `json:"env,omitempty"`
-	PodAnnotations *PodAnnotations `json:"podAnnotations,omitempty"`
+	Istio               *Istio                `json:"istio,omitempty"`
+	Logging             *LoggingCfg           `json:"logging,omitempty"`
+	Resources           *Resources            `json:"resources,omitempty"`
+	Env                 EnvVars               `json:"env,omitempty"`
+	PodAnnotations      *PodAnnotations       `json:"podAnnotations,omitempty"`
+	Replicas            *Replicas             `json:"replicas,omitempty"`
+	PodDisruptionBudget *PodDisruptionBudgets `json:"podDisruptionBudget,omitempty"`
+	Scheduling          *Scheduling           `json:"scheduling,omitempty"`
+	// PriorityClassName is used by all KEDA components; the keda-priority-class is managed by keda-manager and used by default
+	PriorityClassName PriorityClassName `json:"priorityClassName,omitempty"`
+	// ComponentPriorityClassNames overrides PriorityClassName for the given components
+	ComponentPriorityClassNames *PriorityClassNames `json:"componentPriorityClassNames,omitempty"`
+	// ReconcilePolicy set to Plan computes the changes without applying them and reports them in status.plan
+	ReconcilePolicy ReconcilePolicy `json:"reconcilePolicy,omitempty"`
+	// DeletionPolicy defines how KEDA is removed from the cluster, Safe is used by default
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+	HTTPAddon      *HTTPAddon     `json:"httpAddon,omitempty"`
+	// ExternalScalers are deployed next to KEDA and removed together with the module
+	// +listType=map
+	// +listMapKey=name
+	ExternalScalers []ExternalScaler `json:"externalScalers,omitempty"`
+	// WatchNamespaces restricts keda-operator to the given namespaces; the operator permissions are granted
+	// with Roles and RoleBindings in these namespaces instead of the cluster-wide ClusterRole, all namespaces are watched by default
+	// +listType=set
+	WatchNamespaces []WatchNamespace `json:"watchNamespaces,omitempty"`
+}
+
+// +kubebuilder:validation:MaxLength=63
+// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+type WatchNamespace string
+
+func (s *KedaSpec) IsPlanMode() bool {
+	return s.ReconcilePolicy == ReconcilePolicyPlan
+}
+
+// WatchNamespace returns the value of the WATCH_NAMESPACE env of the KEDA components,
+// an empty value means all namespaces are watched
+func (s *KedaSpec) WatchNamespace() string {
+	namespaces := make([]string, 0, len(s.WatchNamespaces))
+	for _, ns := range s.WatchNamespaces {
+		namespaces = append(namespaces, string(ns))
+	}
+	return strings.Join(namespaces, ",")
 }
 
 type EnvVars []corev1.EnvVar
@@ -315,6 +555,21 @@ func (v *EnvVars) Sanitize() {
 	*v = append(*v, required...)
 }
 
+// WithWatchNamespace returns a copy of the env vars with the WATCH_NAMESPACE value set,
+// Sanitize keeps the value instead of injecting the empty one
+func (v EnvVars) WithWatchNamespace(namespace string) EnvVars {
+	result := make(EnvVars, 0, len(v)+1)
+	for _, env := range v {
+		if env.Name != watchNamespace.Name {
+			result = append(result, env)
+		}
+	}
+	return append(result, corev1.EnvVar{
+		Name:  watchNamespace.Name,
+		Value: namespace,
+	})
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:categories={kyma-modules,kyma-keda}
@@ -419,6 +674,23 @@ func (k *Keda) UpdateStateDeletionTrue(c ConditionType, r ConditionReason, msg s
 	meta.SetStatusCondition(&k.Status.Conditions, condition)
 }
 
+// UpdateCondition sets the condition without changing the state of the Keda CR
+func (k *Keda) UpdateCondition(c ConditionType, status metav1.ConditionStatus, r ConditionReason, msg string) {
+	condition := metav1.Condition{
+		Type:               string(c),
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(r),
+		Message:            msg,
+	}
+	meta.SetStatusCondition(&k.Status.Conditions, condition)
+}
+
+// IsReconcilePaused returns true if the reconciliation of the KEDA resources is paused with the reconcile-paused annotation
+func (k *Keda) IsReconcilePaused() bool {
+	return k.GetAnnotations()[AnnotationReconcilePaused] == "true"
+}
+
 func (k *Keda) UpdateServed(served string) {
 	k.Status.Served = served
 }
@@ -427,11 +699,107 @@ func (k *Keda) IsServedEmpty() bool {
 	return k.Status.Served == ""
 }
 
+type ComponentStatus struct {
+	Name              string `json:"name"`
+	Image             string `json:"image,omitempty"`
+	DesiredReplicas   int32  `json:"desiredReplicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+	Version           string `json:"version,omitempty"`
+	LastError         string `json:"lastError,omitempty"`
+}
+
+type PlannedObjectChange struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	// Fields lists the changed field paths, capped at a fixed number of entries
+	Fields []string `json:"fields,omitempty"`
+}
+
+type PlanStatus struct {
+	ObjectsChanged int                   `json:"objectsChanged"`
+	FieldsChanged  int                   `json:"fieldsChanged"`
+	Objects        []PlannedObjectChange `json:"objects,omitempty"`
+}
+
+// InventoryEntry identifies an object applied by keda-manager
+type InventoryEntry struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+type AddonUpgradeStatus struct {
+	FromVersion   string      `json:"fromVersion"`
+	FromNamespace string      `json:"fromNamespace"`
+	ToVersion     string      `json:"toVersion"`
+	ToNamespace   string      `json:"toNamespace"`
+	StartedAt     metav1.Time `json:"startedAt"`
+	// RolledBack is set when the new installation was not ready in time and the previous one was restored
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// PreviousInventory lists the objects of the previous installation,
+	// the ones that are not part of the new installation are pruned once it is verified
+	PreviousInventory []InventoryEntry `json:"previousInventory,omitempty"`
+	// FromSource is the manifests source of the previous installation, it is restored from it on rollback
+	FromSource *AddonSourceStatus `json:"fromSource,omitempty"`
+}
+
+// AddonSourceStatus records where the manifests of an add-on installation were read from
+type AddonSourceStatus struct {
+	ManifestsConfigMap string                `json:"manifestsConfigMap,omitempty"`
+	ManifestDigests    *AddonManifestDigests `json:"manifestDigests,omitempty"`
+}
+
+// AddonStatus tracks the installation of an add-on managed by keda-manager
+type AddonStatus struct {
+	// Name identifies the add-on, e.g. http
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Inventory lists the add-on objects applied in the last installation,
+	// the add-on is removed based on it
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+	// Source is the manifests source of the last installation, the bundled or downloaded manifests are used if not set
+	Source *AddonSourceStatus `json:"source,omitempty"`
+	// Upgrade tracks the add-on version or namespace change until the new installation is verified
+	Upgrade *AddonUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// OrphanedResource identifies a custom resource that blocks the safe deletion of the Keda module
+type OrphanedResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+type OrphanReport struct {
+	// Total is the number of all blocking resources, Resources is capped at a fixed number of entries
+	Total     int                `json:"total"`
+	Resources []OrphanedResource `json:"resources,omitempty"`
+}
+
 type Status struct {
-	State       string             `json:"state"`
-	Served      string             `json:"served"`
-	KedaVersion string             `json:"kedaVersion,omitempty"`
-	Conditions  []metav1.Condition `json:"conditions,omitempty"`
+	State              string            `json:"state"`
+	Served             string            `json:"served"`
+	ObservedGeneration int64             `json:"observedGeneration,omitempty"`
+	KedaVersion        string            `json:"kedaVersion,omitempty"`
+	Components         []ComponentStatus `json:"components,omitempty"`
+	Plan               *PlanStatus       `json:"plan,omitempty"`
+	// Inventory lists the objects applied in the last successful reconciliation,
+	// objects removed from the manifest are pruned based on it
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+	// Addons lists the installations of the add-ons managed by keda-manager
+	// +listType=map
+	// +listMapKey=name
+	Addons []AddonStatus `json:"addons,omitempty"`
+	// Orphans lists the resources that block the deletion with the Safe deletion policy
+	Orphans    *OrphanReport      `json:"orphans,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -450,7 +818,7 @@ func init() {
 // SetAddonCondition sets an addon-specific condition on the Keda CR status.
 func SetAddonCondition(instance *Keda, status metav1.ConditionStatus, reason, msg string) {
 	condition := metav1.Condition{
-		Type:               conditionTypeAddon,
+		Type:               string(ConditionTypeAddonInstalled),
 		Status:             status,
 		LastTransitionTime: metav1.Now(),
 		Reason:             reason,
@@ -459,11 +827,54 @@ func SetAddonCondition(instance *Keda, status metav1.ConditionStatus, reason, ms
 	meta.SetStatusCondition(&instance.Status.Conditions, condition)
 }
 
-// AddonCfg holds the addon configuration read from the Keda CR annotations.
+// GetAddonStatus returns the recorded installation of the add-on, nil if the add-on is not installed
+func (k *Keda) GetAddonStatus(name string) *AddonStatus {
+	for i := range k.Status.Addons {
+		if k.Status.Addons[i].Name == name {
+			return &k.Status.Addons[i]
+		}
+	}
+	return nil
+}
+
+// SetAddonStatus records the installation of the add-on
+func (k *Keda) SetAddonStatus(status AddonStatus) {
+	if current := k.GetAddonStatus(status.Name); current != nil {
+		*current = status
+		return
+	}
+	k.Status.Addons = append(k.Status.Addons, status)
+}
+
+// RemoveAddonStatus removes the recorded installation of the add-on
+func (k *Keda) RemoveAddonStatus(name string) {
+	addons := k.Status.Addons[:0]
+	for _, status := range k.Status.Addons {
+		if status.Name != name {
+			addons = append(addons, status)
+		}
+	}
+	if len(addons) == 0 {
+		addons = nil
+	}
+	k.Status.Addons = addons
+}
+
+// AddonCfg holds the addon configuration read from the Keda CR annotations and spec.
 type AddonCfg struct {
 	Enabled        bool
 	Namespace      string
 	IstioInjection bool
+	Version        string
+	// ManifestsConfigMap is the name of the ConfigMap with the add-on manifests
+	ManifestsConfigMap string
+	// CRDsDigest and ManifestDigest override the digests of the downloaded add-on manifests
+	CRDsDigest     string
+	ManifestDigest string
+	// Interceptor, Scaler and Operator override the settings of the add-on components
+	Interceptor *AddonComponentCfg
+	Scaler      *AddonComponentCfg
+	Operator    *AddonComponentCfg
 }
 
 func (a AddonCfg) EffectiveNamespace() string {
@@ -473,16 +884,51 @@ func (a AddonCfg) EffectiveNamespace() string {
 	return a.Namespace
 }
 
+// ReadAddonCfg reads the addon configuration from spec.httpAddon; the deprecated annotations
+// are still honoured for the settings that are not defined in the spec
 func ReadAddonCfg(instance *Keda) AddonCfg {
+	cfg := AddonCfg{}
 	ann := instance.GetAnnotations()
-	if ann == nil {
-		return AddonCfg{}
+	if ann != nil {
+		cfg.Enabled = strings.EqualFold(ann[AnnotationAddonEnabled], "true")
+		cfg.Namespace = ann[AnnotationAddonNamespace]
+		cfg.IstioInjection = strings.EqualFold(ann[AnnotationAddonIstioInjection], "true")
 	}
-	return AddonCfg{
-		Enabled:        strings.EqualFold(ann[AnnotationAddonEnabled], "true"),
-		Namespace:      ann[AnnotationAddonNamespace],
-		IstioInjection: strings.EqualFold(ann[AnnotationAddonIstioInjection], "true"),
+
+	spec := instance.Spec.HTTPAddon
+	if spec == nil {
+		return cfg
+	}
+	if spec.Enabled != nil {
+		cfg.Enabled = *spec.Enabled
+	}
+	if spec.Namespace != "" {
+		cfg.Namespace = spec.Namespace
+	}
+	if spec.Istio != nil {
+		cfg.IstioInjection = spec.Istio.EnabledSidecarInjection
+	}
+	cfg.Version = string(spec.Version)
+	cfg.ManifestsConfigMap = spec.ManifestsConfigMap
+	if spec.ManifestDigests != nil {
+		cfg.CRDsDigest = string(spec.ManifestDigests.CRDs)
+		cfg.ManifestDigest = string(spec.ManifestDigests.Manifest)
+	}
+	cfg.Interceptor = spec.Interceptor
+	cfg.Scaler = spec.Scaler
+	cfg.Operator = spec.Operator
+	return cfg
+}
+
+// DeprecatedAddonAnnotations returns the deprecated addon annotations set on the Keda CR
+func DeprecatedAddonAnnotations(instance *Keda) []string {
+	var used []string
+	for _, key := range []string{AnnotationAddonEnabled, AnnotationAddonNamespace, AnnotationAddonIstioInjection} {
+		if _, found := instance.GetAnnotations()[key]; found {
+			used = append(used, key)
+		}
 	}
+	return used
 }
 
 // SetAnnotation updates (or removes when value is empty) an annotation on the Keda CR in-memory.
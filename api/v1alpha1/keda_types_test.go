@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -204,6 +205,14 @@ func Test_LoggingCommonCfg_Sanitize(t *testing.T) {
 	})
 }
 
+func TestLoggingCommonCfgFromArgs(t *testing.T) {
+	cfg := LoggingCommonCfgFromArgs([]string{"--v=0", "--zap-log-level=error", "--zap-encoder=json", "--leader-elect"})
+
+	require.Equal(t, CommonLogLevelError, *cfg.Level)
+	require.Equal(t, LogFormatJSON, *cfg.Format)
+	require.Nil(t, cfg.TimeEncoding)
+}
+
 func TestReadAddonCfg(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -266,3 +275,26 @@ func TestSetAnnotation(t *testing.T) {
 		require.False(t, exists)
 	})
 }
+
+func TestEnvVars_WithWatchNamespace(t *testing.T) {
+	t.Run("set watched namespaces", func(t *testing.T) {
+		spec := KedaSpec{
+			Env:             EnvVars{{Name: "KEDA_HTTP_MIN_TLS_VERSION", Value: "TLS13"}},
+			WatchNamespaces: []WatchNamespace{"team-a", "team-b"},
+		}
+
+		envs := spec.Env.WithWatchNamespace(spec.WatchNamespace())
+		envs.Sanitize()
+
+		require.Contains(t, envs, corev1.EnvVar{Name: "WATCH_NAMESPACE", Value: "team-a,team-b"})
+		require.Contains(t, envs, corev1.EnvVar{Name: "KEDA_HTTP_MIN_TLS_VERSION", Value: "TLS13"})
+		require.Len(t, envs, 6)
+		require.Len(t, spec.Env, 1)
+	})
+
+	t.Run("override WATCH_NAMESPACE env", func(t *testing.T) {
+		envs := EnvVars{{Name: "WATCH_NAMESPACE", Value: "other"}}.WithWatchNamespace("team-a")
+
+		require.Equal(t, EnvVars{{Name: "WATCH_NAMESPACE", Value: "team-a"}}, envs)
+	})
+}
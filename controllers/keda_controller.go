@@ -22,6 +22,7 @@ import (
 	"os"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/addon"
 	"github.com/kyma-project/keda-manager/pkg/reconciler"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -173,13 +174,16 @@ func (r *kedaReconciler) retriggerAllKedaCRs(ctx context.Context, e event.Delete
 	}
 }
 
-func NewKedaReconciler(c client.Client, r record.EventRecorder, log *zap.SugaredLogger, o []unstructured.Unstructured, httpClient *http.Client) KedaReconciler {
+func NewKedaReconciler(c client.Client, r record.EventRecorder, log *zap.SugaredLogger, o []unstructured.Unstructured, httpClient *http.Client, addonEndpoints addon.Endpoints) KedaReconciler {
 	return &kedaReconciler{
 		log: log,
 		Cfg: reconciler.Cfg{
-			Finalizer:  v1alpha1.Finalizer,
-			Objs:       o,
-			HTTPClient: httpClient,
+			Finalizer:      v1alpha1.Finalizer,
+			Objs:           o,
+			Addons:         reconciler.DefaultAddons(),
+			HTTPClient:     httpClient,
+			AddonCache:     addon.NewCache(),
+			AddonEndpoints: addonEndpoints,
 		},
 		K8s: reconciler.K8s{
 			APIServerIP:   os.Getenv("KUBERNETES_PORT_443_TCP_ADDR"),
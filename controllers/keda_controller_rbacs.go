@@ -36,7 +36,7 @@ package controllers
 
 // KEDA resources
 //+kubebuilder:rbac:groups="keda.sh",resources=clustertriggerauthentications;clustertriggerauthentications/status;scaledjobs;scaledjobs/finalizers;scaledjobs/status;scaledobjects;scaledobjects/finalizers;scaledobjects/status;triggerauthentications;triggerauthentications/status,verbs=create;delete;list;patch;update;watch
-//+kubebuilder:rbac:groups="eventing.keda.sh",resources=cloudeventsources;cloudeventsources/status;clustercloudeventsources;clustercloudeventsources/status,verbs=list;patch;update;watch
+//+kubebuilder:rbac:groups="eventing.keda.sh",resources=cloudeventsources;cloudeventsources/status;clustercloudeventsources;clustercloudeventsources/status,verbs=create;delete;list;patch;update;watch
 //+kubebuilder:rbac:groups="discovery.k8s.io",resources="endpointslices",verbs=list;watch
 
 // HTTP add-on resources – the keda-manager must hold at least the same
@@ -52,6 +52,7 @@ package controllers
 
 // Webhooks
 //+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=create;delete;list;patch;update;watch
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=patch;update
 
 // Kyma Keda operator resources
 //+kubebuilder:rbac:groups=operator.kyma-project.io,resources=kedas,verbs=create;delete;list;patch;update;watch
@@ -61,5 +62,11 @@ package controllers
 // Network policies
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;list;patch;update;watch
 
+// Pod disruption budgets
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;list;patch;update;watch
+
+// Priority classes
+//+kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=create;delete;list;patch;update;watch
+
 // Istio PeerAuthentication for HTTP add-on sidecar metrics scrape
 //+kubebuilder:rbac:groups=security.istio.io,resources=peerauthentications,verbs=create;delete;list;patch;update;watch
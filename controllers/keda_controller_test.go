@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -98,8 +99,99 @@ var _ = Describe("Keda controller", func() {
 			shouldDeleteKeda(h, kedaName)
 		})
 	})
+
+	Context("When creating multiple instances", func() {
+		const (
+			namespaceName = "keda-election"
+			olderName     = "keda-b"
+			youngerName   = "keda-a"
+		)
+
+		It("Only the oldest instance should be served", func() {
+			h := testHelper{
+				ctx:           context.Background(),
+				namespaceName: namespaceName,
+			}
+			h.createNamespace()
+
+			h.createKeda(olderName, v1alpha1.KedaSpec{})
+			// creation timestamps have a precision of seconds
+			time.Sleep(time.Second + 100*time.Millisecond)
+			h.createKeda(youngerName, v1alpha1.KedaSpec{})
+
+			shouldElectServedKeda(h, olderName, youngerName, v1alpha1.ConditionReasonKedaDuplicated)
+
+			shouldDemoteConflictingKeda(h, olderName, youngerName)
+
+			shouldDeleteAllKedas(h, olderName, youngerName)
+		})
+	})
 })
 
+func shouldElectServedKeda(h testHelper, servedName, notServedName string, reason v1alpha1.ConditionReason) {
+	Eventually(h.createGetKedaServedFunc(notServedName)).
+		WithPolling(time.Second * 2).
+		WithTimeout(time.Second * 20).
+		Should(Equal(v1alpha1.ServedFalse))
+
+	Eventually(h.createGetKedaServedFunc(servedName)).
+		WithPolling(time.Second * 2).
+		WithTimeout(time.Second * 20).
+		Should(Equal(v1alpha1.ServedTrue))
+
+	var keda v1alpha1.Keda
+	Expect(h.createGetKubernetesObjectFunc(notServedName, &keda)()).To(BeTrue())
+	Expect(keda.Status.State).To(Equal(v1alpha1.StateError))
+	condition := meta.FindStatusCondition(keda.Status.Conditions, string(v1alpha1.ConditionTypeInstalled))
+	Expect(condition).NotTo(BeNil())
+	Expect(condition.Reason).To(Equal(string(reason)))
+	Expect(condition.Message).To(ContainSubstring(fmt.Sprintf("%s/%s", h.namespaceName, servedName)))
+}
+
+func shouldDemoteConflictingKeda(h testHelper, servedName, conflictingName string) {
+	// arrange - simulate the race of two instances marked as served
+	Eventually(func() error {
+		var keda v1alpha1.Keda
+		if _, err := h.createGetKubernetesObjectFunc(conflictingName, &keda)(); err != nil {
+			return err
+		}
+		keda.UpdateServed(v1alpha1.ServedTrue)
+		return k8sClient.Status().Update(h.ctx, &keda)
+	}).
+		WithPolling(time.Second).
+		WithTimeout(time.Second * 10).
+		Should(Succeed())
+
+	// status changes are filtered out, annotate the instance to trigger the reconciliation
+	Eventually(func() error {
+		var keda v1alpha1.Keda
+		if _, err := h.createGetKubernetesObjectFunc(conflictingName, &keda)(); err != nil {
+			return err
+		}
+		keda.SetAnnotations(map[string]string{"test.kyma-project.io/reconcile": time.Now().String()})
+		return k8sClient.Update(h.ctx, &keda)
+	}).
+		WithPolling(time.Second).
+		WithTimeout(time.Second * 10).
+		Should(Succeed())
+
+	// assert
+	shouldElectServedKeda(h, servedName, conflictingName, v1alpha1.ConditionReasonServedConflict)
+}
+
+func shouldDeleteAllKedas(h testHelper, kedaNames ...string) {
+	for _, kedaName := range kedaNames {
+		var keda v1alpha1.Keda
+		Expect(h.createGetKubernetesObjectFunc(kedaName, &keda)()).To(BeTrue())
+		Expect(k8sClient.Delete(h.ctx, &keda)).To(Succeed())
+	}
+
+	Eventually(h.getKedaCount).
+		WithPolling(time.Second * 2).
+		WithTimeout(time.Second * 20).
+		Should(Equal(0))
+}
+
 func shouldCreateKeda(h testHelper, kedaName, kedaDeploymentName, metricsDeploymentName, kedaAdmissionWebhookDeploymentName string, kedaSpec v1alpha1.KedaSpec) {
 	// act
 	h.createKeda(kedaName, kedaSpec)
@@ -223,6 +315,16 @@ func (h *testHelper) createGetKedaStateFunc(kedaName string) func() (string, err
 	}
 }
 
+func (h *testHelper) createGetKedaServedFunc(kedaName string) func() (string, error) {
+	return func() (string, error) {
+		var keda v1alpha1.Keda
+		if _, err := h.createGetKubernetesObjectFunc(kedaName, &keda)(); err != nil {
+			return "", err
+		}
+		return keda.Status.Served, nil
+	}
+}
+
 func (h *testHelper) getKedaState(kedaName string) (string, error) {
 	var emptyState = ""
 	var keda v1alpha1.Keda
@@ -21,8 +21,8 @@ import (
 	"crypto/fips140"
 	"flag"
 	"fmt"
-	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/go-logr/zapr"
@@ -37,6 +37,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -47,7 +48,9 @@ import (
 
 	operatorv1alpha1 "github.com/kyma-project/keda-manager/api/v1alpha1"
 	"github.com/kyma-project/keda-manager/controllers"
+	"github.com/kyma-project/keda-manager/pkg/addon"
 	"github.com/kyma-project/keda-manager/pkg/resources"
+	kedawebhook "github.com/kyma-project/keda-manager/pkg/webhook"
 	"github.com/kyma-project/manager-toolkit/logging/config"
 	//+kubebuilder:scaffold:imports
 )
@@ -73,6 +76,10 @@ func main() {
 	var probeAddr string
 	var configPath string
 	var enableLeaderElection bool
+	var addonEndpoints addon.Endpoints
+	var addonClientCfg addon.ClientCfg
+	var enableWebhooks bool
+	var webhookCertDir string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -80,6 +87,21 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&addonEndpoints.TagsURL, "addon-tags-url", addon.DefaultTagsURL,
+		"The URL listing the HTTP add-on releases in the GitHub tags API format.")
+	flag.StringVar(&addonEndpoints.ReleaseURL, "addon-release-url", addon.DefaultReleaseURL,
+		"The URL of the HTTP add-on release assets, {version} is replaced with the add-on version.")
+	flag.StringVar(&addonClientCfg.ProxyURL, "addon-proxy-url", "",
+		"The proxy used to download the HTTP add-on releases. "+
+			"The HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used by default.")
+	flag.StringVar(&addonClientCfg.CABundlePath, "addon-ca-bundle", "",
+		"Path to a PEM file with additional CA certificates trusted when downloading the HTTP add-on releases.")
+	flag.DurationVar(&addonClientCfg.Timeout, "addon-download-timeout", 30*time.Second,
+		"The timeout of the HTTP add-on release downloads.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", true,
+		"Enable the Keda CR admission webhooks. The Keda CR is still validated by the controller when disabled.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs"),
+		"The directory the self-signed webhook serving certificate is written to.")
 	flag.Parse()
 
 	// Load configuration - from file if provided, otherwise from environment
@@ -129,7 +151,8 @@ func main() {
 			BindAddress: metricsAddr,
 		},
 		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
-			Port: 9443,
+			Port:    9443,
+			CertDir: webhookCertDir,
 		}),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -154,7 +177,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	httpClient := &http.Client{Timeout: 30 * time.Second}
+	httpClient, err := addon.NewHTTPClient(addonClientCfg)
+	if err != nil {
+		fmt.Printf("unable to create addon http client: %v\n", err)
+		os.Exit(1)
+	}
 
 	kedaReconciler := controllers.NewKedaReconciler(
 		mgr.GetClient(),
@@ -162,11 +189,18 @@ func main() {
 		logWithCtx,
 		data,
 		httpClient,
+		addonEndpoints,
 	)
 	if err = kedaReconciler.SetupWithManager(mgr); err != nil {
 		fmt.Printf("unable to create controller: %v\n", err)
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err = setupWebhooks(ctx, mgr, logWithCtx, webhookCertDir, data); err != nil {
+			fmt.Printf("unable to set up webhooks: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -184,6 +218,37 @@ func main() {
 	}
 }
 
+// setupWebhooks provides the webhook serving certificate and registers the Keda CR webhooks;
+// the certificate is written before the manager starts, because the webhook server loads it on start
+func setupWebhooks(ctx context.Context, mgr ctrl.Manager, log *zap.SugaredLogger, certDir string, objs []unstructured.Unstructured) error {
+	// the manager cache is not started yet, so the certificate is provided with a direct client
+	c, err := ctrlclient.New(mgr.GetConfig(), ctrlclient.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+
+	certManager := &kedawebhook.CertificateManager{
+		Client: c,
+		Log:    log.Named("webhook-cert"),
+		Cfg: kedawebhook.CertificateCfg{
+			SecretName:            "keda-manager-webhook-cert",
+			Namespace:             "kyma-system",
+			ServiceName:           "keda-webhook-service",
+			CertDir:               certDir,
+			MutatingWebhookName:   "keda-mutating-webhook-configuration",
+			ValidatingWebhookName: "keda-validating-webhook-configuration",
+		},
+	}
+	if err := certManager.EnsureCertificate(ctx); err != nil {
+		return err
+	}
+	if err := mgr.Add(certManager); err != nil {
+		return err
+	}
+
+	return kedawebhook.SetupKedaWebhookWithManager(mgr, objs)
+}
+
 // isFIPS140Only checks if the application is running in FIPS 140 exclusive mode.
 func isFIPS140Only() bool {
 	return fips140.Enabled() && os.Getenv("GODEBUG") == "fips140=only,tlsmlkem=0"
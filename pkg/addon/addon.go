@@ -17,12 +17,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-const (
-	tagsURL    = "https://api.github.com/repos/kedacore/http-add-on/tags"
-	releaseURL = "https://github.com/kedacore/http-add-on/releases/download/v%s/keda-add-ons-http-%s.yaml"
-	crdURL     = "https://github.com/kedacore/http-add-on/releases/download/v%s/keda-add-ons-http-%s-crds.yaml"
-)
-
 // versionRe validates a semver-like version without a leading "v".
 var versionRe = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+`)
 
@@ -55,8 +49,8 @@ func isTLSError(err error) bool {
 
 // LatestVersion queries the GitHub tags API and returns the latest tag name
 // with the leading "v" stripped.
-func LatestVersion(client *http.Client) (string, error) {
-	resp, err := client.Get(tagsURL)
+func LatestVersion(client *http.Client, endpoints Endpoints) (string, error) {
+	resp, err := client.Get(endpoints.tagsURL())
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch tags: %w", err)
 	}
@@ -82,19 +76,39 @@ func LatestVersion(client *http.Client) (string, error) {
 
 // FetchResources downloads the http-add-on CRDs and manifest for the given
 // version and parses them into unstructured objects. The CRDs are prepended so
-// they are applied before the rest of the resources.
-func FetchResources(client *http.Client, version string) ([]unstructured.Unstructured, error) {
+// they are applied before the rest of the resources. The downloaded files are
+// rejected if they do not match the given digests or if the digests are missing.
+func FetchResources(client *http.Client, endpoints Endpoints, version string, digests Digests) ([]unstructured.Unstructured, error) {
+	version, err := ValidateVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail before downloading anything, the manifests are never applied without verification
+	if !digests.IsComplete() {
+		return nil, fmt.Errorf("%w for version %s, set them in spec.httpAddon.manifestDigests", ErrDigestNotPinned, version)
+	}
+	return fetchResources(client, endpoints, version, digests)
+}
+
+// FetchResourcesToDelete downloads the http-add-on CRDs and manifest like FetchResources, but the files
+// without a pinned digest are accepted. The objects identify the resources of an installation that is
+// removed and must never be applied.
+func FetchResourcesToDelete(client *http.Client, endpoints Endpoints, version string, digests Digests) ([]unstructured.Unstructured, error) {
 	version, err := ValidateVersion(version)
 	if err != nil {
 		return nil, err
 	}
+	return fetchResources(client, endpoints, version, digests)
+}
 
-	crdObjs, err := fetchURL(client, fmt.Sprintf(crdURL, version, version), version)
+func fetchResources(client *http.Client, endpoints Endpoints, version string, digests Digests) ([]unstructured.Unstructured, error) {
+	crdObjs, err := fetchURL(client, endpoints.assetURL(version, crdsFileName(version)), version, digests.CRDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch addon CRDs: %w", err)
 	}
 
-	objs, err := fetchURL(client, fmt.Sprintf(releaseURL, version, version), version)
+	objs, err := fetchURL(client, endpoints.assetURL(version, manifestFileName(version)), version, digests.Manifest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch addon manifest: %w", err)
 	}
@@ -102,12 +116,12 @@ func FetchResources(client *http.Client, version string) ([]unstructured.Unstruc
 	return append(crdObjs, objs...), nil
 }
 
-// fetchURL downloads a single URL and parses it into unstructured objects.
-func fetchURL(client *http.Client, rawURL, version string) ([]unstructured.Unstructured, error) {
+// fetchURL downloads a single URL, verifies its digest if it is pinned and parses it into unstructured objects.
+func fetchURL(client *http.Client, rawURL, version, digest string) ([]unstructured.Unstructured, error) {
 	resp, err := client.Get(rawURL)
 	if err != nil {
 		if isTLSError(err) {
-			return nil, fmt.Errorf("TLS certificate verification failed for %s: %w (ensure CA certificates are available in the container image)", rawURL, err)
+			return nil, fmt.Errorf("TLS certificate verification failed for %s: %w (ensure CA certificates are available in the container image or configure the CA bundle)", rawURL, err)
 		}
 		return nil, fmt.Errorf("failed to download %s: %w", rawURL, err)
 	}
@@ -122,6 +136,12 @@ func fetchURL(client *http.Client, rawURL, version string) ([]unstructured.Unstr
 		return nil, fmt.Errorf("failed to read response from %s: %w", rawURL, err)
 	}
 
+	if digest != "" {
+		if err := verifyDigest(body, digest); err != nil {
+			return nil, fmt.Errorf("refusing to apply %s: %w", rawURL, err)
+		}
+	}
+
 	objs, err := yaml.LoadData(strings.NewReader(string(body)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse response from %s: %w", rawURL, err)
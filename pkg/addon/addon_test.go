@@ -50,20 +50,16 @@ func TestLatestVersion(t *testing.T) {
 		}))
 		defer srv.Close()
 
-		// Override tagsURL via a test server - we need to use fetchURL-style approach
-		// Since tagsURL is a const, we test via httptest by patching the client
-		origURL := tagsURL
-		// We can't override const, so we test LatestVersion indirectly
-		// by using a custom transport that redirects
+		// redirect the default tags URL to the test server
 		client := srv.Client()
 		transport := &urlRewriteTransport{
 			base:    client.Transport,
-			fromURL: origURL,
+			fromURL: DefaultTagsURL,
 			toURL:   srv.URL,
 		}
 		client.Transport = transport
 
-		version, err := LatestVersion(client)
+		version, err := LatestVersion(client, Endpoints{})
 		require.NoError(t, err)
 		require.Equal(t, "0.13.0", version)
 	})
@@ -78,11 +74,11 @@ func TestLatestVersion(t *testing.T) {
 		client := srv.Client()
 		client.Transport = &urlRewriteTransport{
 			base:    client.Transport,
-			fromURL: tagsURL,
+			fromURL: DefaultTagsURL,
 			toURL:   srv.URL,
 		}
 
-		_, err := LatestVersion(client)
+		_, err := LatestVersion(client, Endpoints{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no tags found")
 	})
@@ -96,11 +92,11 @@ func TestLatestVersion(t *testing.T) {
 		client := srv.Client()
 		client.Transport = &urlRewriteTransport{
 			base:    client.Transport,
-			fromURL: tagsURL,
+			fromURL: DefaultTagsURL,
 			toURL:   srv.URL,
 		}
 
-		_, err := LatestVersion(client)
+		_, err := LatestVersion(client, Endpoints{})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "HTTP 500")
 	})
@@ -108,7 +104,7 @@ func TestLatestVersion(t *testing.T) {
 
 func TestFetchResources(t *testing.T) {
 	t.Run("invalid version returns error", func(t *testing.T) {
-		_, err := FetchResources(http.DefaultClient, "bad-version")
+		_, err := FetchResources(http.DefaultClient, Endpoints{}, "bad-version", Digests{})
 		require.Error(t, err)
 	})
 
@@ -140,7 +136,7 @@ metadata:
 			toURL: srv.URL,
 		}
 
-		objs, err := FetchResources(client, "0.13.0")
+		objs, err := FetchResources(client, Endpoints{}, "0.13.0", Digests{CRDs: sha256Hex(crdYAML), Manifest: sha256Hex(manifestYAML)})
 		require.NoError(t, err)
 		require.Len(t, objs, 2)
 		// CRDs should come first
@@ -157,7 +153,7 @@ metadata:
 		client := srv.Client()
 		client.Transport = &prefixRewriteTransport{base: client.Transport, toURL: srv.URL}
 
-		_, err := FetchResources(client, "0.13.0")
+		_, err := FetchResources(client, Endpoints{}, "0.13.0", Digests{CRDs: "crds", Manifest: "manifest"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to fetch addon CRDs")
 	})
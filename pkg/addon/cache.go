@@ -0,0 +1,53 @@
+package addon
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Cache keeps the parsed http-add-on manifests so that they are not downloaded on every reconciliation.
+// A nil Cache is valid and caches nothing.
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[string][]unstructured.Unstructured
+}
+
+func NewCache() *Cache {
+	return &Cache{entries: map[string][]unstructured.Unstructured{}}
+}
+
+// Get returns a copy of the objects stored under the given key
+func (c *Cache) Get(key string) ([]unstructured.Unstructured, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	objs, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	return deepCopyObjs(objs), true
+}
+
+// Set stores a copy of the objects under the given key
+func (c *Cache) Set(key string, objs []unstructured.Unstructured) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = deepCopyObjs(objs)
+}
+
+// the cached objects are modified by the callers (e.g. namespace override), always hand out copies
+func deepCopyObjs(objs []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, *obj.DeepCopy())
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package addon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("returns copies of the cached objects", func(t *testing.T) {
+		cache := NewCache()
+		obj := unstructured.Unstructured{}
+		obj.SetName("keda-add-ons-http-interceptor")
+		obj.SetNamespace("keda")
+
+		cache.Set("0.15.0", []unstructured.Unstructured{obj})
+		obj.SetNamespace("modified")
+
+		objs, found := cache.Get("0.15.0")
+		require.True(t, found)
+		require.Equal(t, "keda", objs[0].GetNamespace())
+
+		objs[0].SetNamespace("modified")
+		objs, _ = cache.Get("0.15.0")
+		require.Equal(t, "keda", objs[0].GetNamespace())
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, found := NewCache().Get("0.15.0")
+		require.False(t, found)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var cache *Cache
+		cache.Set("0.15.0", []unstructured.Unstructured{{}})
+		_, found := cache.Get("0.15.0")
+		require.False(t, found)
+	})
+}
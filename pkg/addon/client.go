@@ -0,0 +1,99 @@
+package addon
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTagsURL    = "https://api.github.com/repos/kedacore/http-add-on/tags"
+	DefaultReleaseURL = "https://github.com/kedacore/http-add-on/releases/download/v{version}"
+
+	// versionPlaceholder is replaced with the add-on version in the release URL
+	versionPlaceholder = "{version}"
+)
+
+// Endpoints defines where the http-add-on releases are downloaded from; the GitHub URLs are used by default
+type Endpoints struct {
+	// TagsURL returns the list of releases in the GitHub tags API format
+	TagsURL string
+	// ReleaseURL is the location of the release assets, {version} is replaced with the add-on version
+	ReleaseURL string
+}
+
+func (e Endpoints) tagsURL() string {
+	if e.TagsURL == "" {
+		return DefaultTagsURL
+	}
+	return e.TagsURL
+}
+
+func (e Endpoints) assetURL(version, asset string) string {
+	releaseURL := e.ReleaseURL
+	if releaseURL == "" {
+		releaseURL = DefaultReleaseURL
+	}
+	releaseURL = strings.ReplaceAll(releaseURL, versionPlaceholder, version)
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(releaseURL, "/"), asset)
+}
+
+// ClientCfg configures the HTTP client used to download the http-add-on releases
+type ClientCfg struct {
+	Timeout time.Duration
+	// ProxyURL is used for all requests, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables are used by default
+	ProxyURL string
+	// CABundlePath points to a PEM file with certificates trusted in addition to the system ones
+	CABundlePath string
+}
+
+// NewHTTPClient builds the HTTP client used to download the http-add-on releases
+func NewHTTPClient(cfg ClientCfg) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", cfg.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundlePath != "" {
+		rootCAs, err := loadCABundle(cfg.CABundlePath)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    rootCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+	}, nil
+}
+
+// loadCABundle returns the system certificate pool extended with the certificates from the given file
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return rootCAs, nil
+}
@@ -0,0 +1,81 @@
+package addon
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	t.Run("GitHub by default", func(t *testing.T) {
+		require.Equal(t, DefaultTagsURL, Endpoints{}.tagsURL())
+		require.Equal(t,
+			"https://github.com/kedacore/http-add-on/releases/download/v0.15.0/keda-add-ons-http-0.15.0.yaml",
+			Endpoints{}.assetURL("0.15.0", manifestFileName("0.15.0")))
+	})
+
+	t.Run("mirror", func(t *testing.T) {
+		endpoints := Endpoints{
+			TagsURL:    "https://mirror.local/tags",
+			ReleaseURL: "https://mirror.local/http-add-on/{version}/",
+		}
+		require.Equal(t, "https://mirror.local/tags", endpoints.tagsURL())
+		require.Equal(t,
+			"https://mirror.local/http-add-on/0.15.0/keda-add-ons-http-0.15.0-crds.yaml",
+			endpoints.assetURL("0.15.0", crdsFileName("0.15.0")))
+	})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("proxy", func(t *testing.T) {
+		client, err := NewHTTPClient(ClientCfg{ProxyURL: "http://proxy.local:3128"})
+		require.NoError(t, err)
+
+		proxy, err := client.Transport.(*http.Transport).Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "github.com"}})
+		require.NoError(t, err)
+		require.Equal(t, "http://proxy.local:3128", proxy.String())
+	})
+
+	t.Run("invalid proxy", func(t *testing.T) {
+		_, err := NewHTTPClient(ClientCfg{ProxyURL: "://proxy"})
+		require.Error(t, err)
+	})
+
+	t.Run("trust CA bundle", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		client, err := NewHTTPClient(ClientCfg{CABundlePath: path})
+		require.NoError(t, err)
+
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("CA bundle without certificates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(path, []byte("invalid"), 0o600))
+
+		_, err := NewHTTPClient(ClientCfg{CABundlePath: path})
+		require.Error(t, err)
+	})
+
+	t.Run("missing CA bundle", func(t *testing.T) {
+		_, err := NewHTTPClient(ClientCfg{CABundlePath: filepath.Join(t.TempDir(), "missing.pem")})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,66 @@
+package addon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDigestMismatch is returned when the downloaded manifest does not match the pinned digest
+	ErrDigestMismatch = errors.New("addon manifest digest mismatch")
+	// ErrDigestNotPinned is returned when no digest is pinned for the downloaded manifest
+	ErrDigestNotPinned = errors.New("addon manifest digest not pinned")
+)
+
+// Digests holds the SHA-256 digests of the http-add-on release assets
+type Digests struct {
+	CRDs     string
+	Manifest string
+}
+
+// IsEmpty returns true if no digest is pinned
+func (d Digests) IsEmpty() bool {
+	return d.CRDs == "" && d.Manifest == ""
+}
+
+// IsComplete returns true if the digests of both release assets are pinned
+func (d Digests) IsComplete() bool {
+	return d.CRDs != "" && d.Manifest != ""
+}
+
+// releaseDigests pins the SHA-256 digests of the http-add-on release assets per version; downloaded manifests
+// of versions without pinned digests are refused. Add an entry with the digests printed by `make addon-manifests`
+// when bumping the add-on version, the bundled manifests are checked against the entry in the tests.
+var releaseDigests = map[string]Digests{}
+
+// PinnedDigests returns the digests pinned for the given version; the digests set in
+// the override take precedence over the ones shipped with keda-manager
+func PinnedDigests(version string, override Digests) Digests {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	digests := releaseDigests[version]
+	if override.CRDs != "" {
+		digests.CRDs = override.CRDs
+	}
+	if override.Manifest != "" {
+		digests.Manifest = override.Manifest
+	}
+	return digests
+}
+
+// verifyDigest returns ErrDigestMismatch if the data does not match the expected digest
+// and ErrDigestNotPinned if no digest is expected
+func verifyDigest(data []byte, expected string) error {
+	if expected == "" {
+		return ErrDigestNotPinned
+	}
+	expected = strings.ToLower(strings.TrimPrefix(expected, "sha256:"))
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if actual != expected {
+		return fmt.Errorf("%w: expected sha256:%s, got sha256:%s", ErrDigestMismatch, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package addon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestPinnedDigests(t *testing.T) {
+	origDigests := releaseDigests
+	releaseDigests = map[string]Digests{"0.15.0": {CRDs: "crds", Manifest: "manifest"}}
+	defer func() { releaseDigests = origDigests }()
+
+	require.Equal(t, Digests{CRDs: "crds", Manifest: "manifest"}, PinnedDigests("v0.15.0", Digests{}))
+	require.Equal(t, Digests{CRDs: "crds", Manifest: "custom"}, PinnedDigests("0.15.0", Digests{Manifest: "custom"}))
+	require.True(t, PinnedDigests("0.16.0", Digests{}).IsEmpty())
+}
+
+func TestVerifyDigest(t *testing.T) {
+	data := []byte("kind: Service")
+	digest := sha256Hex(string(data))
+
+	require.ErrorIs(t, verifyDigest(data, ""), ErrDigestNotPinned)
+	require.NoError(t, verifyDigest(data, digest))
+	require.NoError(t, verifyDigest(data, "sha256:"+digest))
+	require.ErrorIs(t, verifyDigest(data, sha256Hex("kind: Secret")), ErrDigestMismatch)
+}
+
+func TestFetchResourcesDigests(t *testing.T) {
+	crdYAML := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: httpscaledobjects.http.keda.sh
+`
+	manifestYAML := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: keda-add-ons-http-operator
+  namespace: keda
+`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contains(r.URL.Path, "crds") {
+			_, _ = w.Write([]byte(crdYAML))
+			return
+		}
+		_, _ = w.Write([]byte(manifestYAML))
+	}))
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Transport = &prefixRewriteTransport{base: client.Transport, toURL: srv.URL}
+
+	t.Run("matching digests", func(t *testing.T) {
+		objs, err := FetchResources(client, Endpoints{}, "0.13.0", Digests{CRDs: sha256Hex(crdYAML), Manifest: sha256Hex(manifestYAML)})
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+	})
+
+	t.Run("digests not pinned", func(t *testing.T) {
+		var downloads int
+		countingClient := &http.Client{Transport: roundTripCounter{base: client.Transport, count: &downloads}}
+
+		_, err := FetchResources(countingClient, Endpoints{}, "0.13.0", Digests{})
+		require.ErrorIs(t, err, ErrDigestNotPinned)
+
+		_, err = FetchResources(countingClient, Endpoints{}, "0.13.0", Digests{CRDs: sha256Hex(crdYAML)})
+		require.ErrorIs(t, err, ErrDigestNotPinned)
+		require.Zero(t, downloads)
+	})
+
+	t.Run("tampered manifest", func(t *testing.T) {
+		_, err := FetchResources(client, Endpoints{}, "0.13.0", Digests{CRDs: sha256Hex(crdYAML), Manifest: sha256Hex("tampered")})
+		require.ErrorIs(t, err, ErrDigestMismatch)
+	})
+
+	t.Run("resources to delete without pinned digests", func(t *testing.T) {
+		objs, err := FetchResourcesToDelete(client, Endpoints{}, "0.13.0", Digests{})
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+	})
+
+	t.Run("tampered resources to delete", func(t *testing.T) {
+		_, err := FetchResourcesToDelete(client, Endpoints{}, "0.13.0", Digests{Manifest: sha256Hex("tampered")})
+		require.ErrorIs(t, err, ErrDigestMismatch)
+	})
+}
+
+type roundTripCounter struct {
+	base  http.RoundTripper
+	count *int
+}
+
+func (t roundTripCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.count++
+	return t.base.RoundTrip(req)
+}
+
+// TestReleaseDigests ensures the digests of the bundled versions are pinned and match the bundled manifests
+func TestReleaseDigests(t *testing.T) {
+	dir := filepath.Join("..", "..", bundledManifestsDir)
+	versions, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range versions {
+		if !entry.IsDir() {
+			continue
+		}
+		version := entry.Name()
+		digests := PinnedDigests(version, Digests{})
+		require.True(t, digests.IsComplete(), "pin the digests of the bundled version %s printed by `make addon-manifests`", version)
+		for file, digest := range map[string]string{
+			crdsFileName(version):     digests.CRDs,
+			manifestFileName(version): digests.Manifest,
+		} {
+			data, err := os.ReadFile(filepath.Join(dir, version, file))
+			require.NoError(t, err)
+			require.NoError(t, verifyDigest(data, digest), file)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package addon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yamlutil "github.com/kyma-project/keda-manager/pkg/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// bundledManifestsDir contains the add-on manifests shipped with the keda-manager image,
+// one directory per version with the same file names as the GitHub release assets
+var bundledManifestsDir = "addon-manifests"
+
+// ErrManifestsNotBundled is returned when the keda-manager image does not contain the manifests of the requested version
+var ErrManifestsNotBundled = errors.New("addon manifests are not bundled")
+
+func crdsFileName(version string) string {
+	return fmt.Sprintf("keda-add-ons-http-%s-crds.yaml", version)
+}
+
+func manifestFileName(version string) string {
+	return fmt.Sprintf("keda-add-ons-http-%s.yaml", version)
+}
+
+// LoadBundledResources reads the http-add-on CRDs and manifest for the given version from the
+// keda-manager image. The CRDs are prepended so they are applied before the rest of the resources.
+func LoadBundledResources(version string) ([]unstructured.Unstructured, error) {
+	version, err := ValidateVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []unstructured.Unstructured
+	for _, name := range []string{
+		crdsFileName(version),
+		manifestFileName(version),
+	} {
+		path := filepath.Join(bundledManifestsDir, version, name)
+		fileObjs, err := loadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w for version %s", ErrManifestsNotBundled, version)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		objs = append(objs, fileObjs...)
+	}
+	return objs, nil
+}
+
+func loadFile(path string) ([]unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return yamlutil.LoadData(f)
+}
+
+// LoadConfigMapResources parses the http-add-on resources stored in all keys of the ConfigMap.
+// The CRDs are moved to the front so they are applied before the rest of the resources.
+func LoadConfigMapResources(cm *corev1.ConfigMap) ([]unstructured.Unstructured, error) {
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var crds, objs []unstructured.Unstructured
+	for _, key := range keys {
+		keyObjs, err := yamlutil.LoadData(strings.NewReader(cm.Data[key]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s of ConfigMap %s/%s: %w", key, cm.GetNamespace(), cm.GetName(), err)
+		}
+		for _, obj := range keyObjs {
+			if obj.GetKind() == "CustomResourceDefinition" {
+				crds = append(crds, obj)
+				continue
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	if len(crds)+len(objs) == 0 {
+		return nil, fmt.Errorf("ConfigMap %s/%s contains no addon resources", cm.GetNamespace(), cm.GetName())
+	}
+	return append(crds, objs...), nil
+}
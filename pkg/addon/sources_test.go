@@ -0,0 +1,126 @@
+package addon
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testCRDManifest = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: httpscaledobjects.http.keda.sh
+`
+	testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: keda-add-ons-http-interceptor
+  namespace: keda
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: keda-add-ons-http-interceptor-proxy
+  namespace: keda
+`
+)
+
+// TestLoadBundledResources_bundledVersions ensures the manifests bundled in the keda-manager image are loaded
+// and that the default add-on version is among them, so that it is installed without access to GitHub
+func TestLoadBundledResources_bundledVersions(t *testing.T) {
+	origDir := bundledManifestsDir
+	bundledManifestsDir = filepath.Join("..", "..", "addon-manifests")
+	defer func() { bundledManifestsDir = origDir }()
+
+	entries, err := os.ReadDir(bundledManifestsDir)
+	require.NoError(t, err)
+	var bundled []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			bundled = append(bundled, entry.Name())
+		}
+	}
+
+	for _, version := range bundled {
+		objs, err := LoadBundledResources(version)
+		require.NoError(t, err, version)
+
+		kinds := map[string]int{}
+		for _, obj := range objs {
+			kinds[obj.GetKind()]++
+		}
+		require.Equal(t, "CustomResourceDefinition", objs[0].GetKind(), version)
+		require.GreaterOrEqual(t, kinds["Deployment"], 3, version)
+	}
+
+	if !slices.Contains(bundled, v1alpha1.DefaultAddonVersion) {
+		t.Skipf("the default add-on version %s is not bundled, run `make addon-manifests ADDON_VERSION=%s`",
+			v1alpha1.DefaultAddonVersion, v1alpha1.DefaultAddonVersion)
+	}
+}
+
+func TestLoadBundledResources(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "0.15.0"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0.15.0", "keda-add-ons-http-0.15.0-crds.yaml"), []byte(testCRDManifest), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0.15.0", "keda-add-ons-http-0.15.0.yaml"), []byte(testManifest), 0o600))
+
+	origDir := bundledManifestsDir
+	bundledManifestsDir = dir
+	defer func() { bundledManifestsDir = origDir }()
+
+	t.Run("loads bundled version with CRDs first", func(t *testing.T) {
+		objs, err := LoadBundledResources("v0.15.0")
+		require.NoError(t, err)
+		require.Len(t, objs, 3)
+		require.Equal(t, "CustomResourceDefinition", objs[0].GetKind())
+		require.Equal(t, "Deployment", objs[1].GetKind())
+	})
+
+	t.Run("version not bundled", func(t *testing.T) {
+		_, err := LoadBundledResources("0.16.0")
+		require.ErrorIs(t, err, ErrManifestsNotBundled)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := LoadBundledResources("latest")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrManifestsNotBundled)
+	})
+}
+
+func TestLoadConfigMapResources(t *testing.T) {
+	t.Run("loads all keys with CRDs first", func(t *testing.T) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "addon-manifests", Namespace: "kyma-system"},
+			Data: map[string]string{
+				"a-manifest.yaml": testManifest,
+				"b-crds.yaml":     testCRDManifest,
+			},
+		}
+
+		objs, err := LoadConfigMapResources(cm)
+		require.NoError(t, err)
+		require.Len(t, objs, 3)
+		require.Equal(t, "CustomResourceDefinition", objs[0].GetKind())
+		require.Equal(t, "Deployment", objs[1].GetKind())
+		require.Equal(t, "Service", objs[2].GetKind())
+	})
+
+	t.Run("empty ConfigMap", func(t *testing.T) {
+		_, err := LoadConfigMapResources(&corev1.ConfigMap{})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		_, err := LoadConfigMapResources(&corev1.ConfigMap{Data: map[string]string{"manifest.yaml": "kind: [a"}})
+		require.Error(t, err)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
@@ -13,12 +15,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const guardAddonInUseRequeue = 30 * time.Second
+
 func ensureNamespace(ctx context.Context, r *fsm, namespace string) error {
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -36,185 +39,66 @@ func ensureNamespace(ctx context.Context, r *fsm, namespace string) error {
 	return nil
 }
 
-const (
-	istioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
-	istioExcludeInboundPortsValue      = "9090"
-	istioSidecarInjectAnnotation       = "sidecar.istio.io/inject"
-	kymaModuleLabel                    = "kyma-project.io/module"
-	kymaModuleLabelValue               = "keda"
-
-	httpScaledObjectGroup   = "http.keda.sh"
-	httpScaledObjectVersion = "v1alpha1"
-	httpScaledObjectKind    = "HTTPScaledObject"
-
-	guardAddonInUseRequeue = 30 * time.Second
-)
-
-var namespaceEnvVars = map[string]struct{}{
-	"KEDA_HTTP_SCALER_TARGET_ADMIN_NAMESPACE": {},
-	"KEDA_HTTP_OPERATOR_NAMESPACE":            {},
-}
-
-func overrideNamespace(objs []unstructured.Unstructured, namespace string, istioInjection bool) {
-	for i := range objs {
-		obj := &objs[i]
-		if obj.GetNamespace() != "" {
-			obj.SetNamespace(namespace)
-		}
-		applyCommonMetadataLabels(obj)
-		switch obj.GetKind() {
-		case "ClusterRoleBinding", "RoleBinding":
-			patchSubjectsNamespace(obj, namespace)
-		case "Deployment":
-			patchDeploymentEnvNamespace(obj, namespace)
-			patchDeploymentPodTemplateLabels(obj)
-			if istioInjection {
-				patchDeploymentIstioExcludePortsAnnotation(obj)
-				patchDeploymentIstioSidecarAnnotation(obj, "true")
-			} else {
-				patchDeploymentIstioSidecarAnnotation(obj, "false")
-			}
-		}
-	}
-}
-
-// applyCommonMetadataLabels stamps the standard Kyma module labels
-// (kyma-project.io/module=keda, app.kubernetes.io/part-of=keda-manager,
-// app.kubernetes.io/managed-by=keda-manager) on the object's top-level
-// metadata so add-on resources (Deployments, Services, etc.) are discoverable
-// via the same label selectors as the rest of the Keda module.
-func applyCommonMetadataLabels(obj *unstructured.Unstructured) {
-	labels := obj.GetLabels()
-	if labels == nil {
-		labels = map[string]string{}
-	}
-	obj.SetLabels(setCommonLabels(labels))
-}
-
-func patchDeploymentIstioExcludePortsAnnotation(obj *unstructured.Unstructured) {
-	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
-	if annotations == nil {
-		annotations = map[string]string{}
-	}
-	if annotations[istioExcludeInboundPortsAnnotation] == istioExcludeInboundPortsValue {
-		return
-	}
-	annotations[istioExcludeInboundPortsAnnotation] = istioExcludeInboundPortsValue
-	_ = unstructured.SetNestedStringMap(obj.Object, annotations, "spec", "template", "metadata", "annotations")
+// addonSource defines where the add-on manifests are read from and the digests the downloaded manifests are verified with
+type addonSource struct {
+	configMap client.ObjectKey
+	digests   addon.Digests
+	// toDelete is set when the manifests only identify the objects of an installation that is removed,
+	// the downloaded manifests are then accepted without pinned digests
+	toDelete bool
 }
 
-func patchDeploymentIstioSidecarAnnotation(obj *unstructured.Unstructured, value string) {
-	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
-	if annotations == nil {
-		annotations = map[string]string{}
-	}
-	if annotations[istioSidecarInjectAnnotation] == value {
-		return
+// status returns the source recorded in the add-on status, nil for the bundled or downloaded manifests verified with the pinned digests
+func (src addonSource) status() *v1alpha1.AddonSourceStatus {
+	if src.configMap.Name == "" && src.digests == (addon.Digests{}) {
+		return nil
 	}
-	annotations[istioSidecarInjectAnnotation] = value
-	_ = unstructured.SetNestedStringMap(obj.Object, annotations, "spec", "template", "metadata", "annotations")
-}
-
-// patchDeploymentPodTemplateLabels stamps `kyma-project.io/module=keda` on
-// the Deployment's pod template so add-on Pods (interceptor, operator, scaler)
-// are discoverable via the standard Kyma module label selector.
-func patchDeploymentPodTemplateLabels(obj *unstructured.Unstructured) {
-	labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
-	if labels == nil {
-		labels = map[string]string{}
-	}
-	if labels[kymaModuleLabel] == kymaModuleLabelValue {
-		return
-	}
-	labels[kymaModuleLabel] = kymaModuleLabelValue
-	_ = unstructured.SetNestedStringMap(obj.Object, labels, "spec", "template", "metadata", "labels")
-}
-
-func patchDeploymentEnvNamespace(obj *unstructured.Unstructured, namespace string) {
-	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
-	if err != nil || !found {
-		return
-	}
-	changed := false
-	for ci, rawC := range containers {
-		container, ok := rawC.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		envList, ok := container["env"].([]interface{})
-		if !ok {
-			continue
-		}
-		for ei, rawE := range envList {
-			envVar, ok := rawE.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			name, _ := envVar["name"].(string)
-			if _, match := namespaceEnvVars[name]; match && envVar["value"] != namespace {
-				envVar["value"] = namespace
-				envList[ei] = envVar
-				changed = true
-			}
+	status := &v1alpha1.AddonSourceStatus{ManifestsConfigMap: src.configMap.Name}
+	if src.digests != (addon.Digests{}) {
+		status.ManifestDigests = &v1alpha1.AddonManifestDigests{
+			CRDs:     v1alpha1.ManifestDigest(src.digests.CRDs),
+			Manifest: v1alpha1.ManifestDigest(src.digests.Manifest),
 		}
-		container["env"] = envList
-		containers[ci] = container
-	}
-	if changed {
-		_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
 	}
+	return status
 }
 
-func patchSubjectsNamespace(obj *unstructured.Unstructured, namespace string) {
-	subjects, found, err := unstructured.NestedSlice(obj.Object, "subjects")
-	if err != nil || !found {
-		return
-	}
-	changed := false
-	for j, raw := range subjects {
-		subj, ok := raw.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if kind, _, _ := unstructured.NestedString(subj, "kind"); kind != "ServiceAccount" {
-			continue
-		}
-		if ns, _, _ := unstructured.NestedString(subj, "namespace"); ns != "" && ns != namespace {
-			subj["namespace"] = namespace
-			subjects[j] = subj
-			changed = true
-		}
+// addonSourceFromStatus returns the source of an installation recorded in the add-on status
+func addonSourceFromStatus(instance *v1alpha1.Keda, status *v1alpha1.AddonSourceStatus) addonSource {
+	var src addonSource
+	if status == nil {
+		return src
+	}
+	if status.ManifestsConfigMap != "" {
+		src.configMap = client.ObjectKey{Namespace: instance.GetNamespace(), Name: status.ManifestsConfigMap}
 	}
-	if changed {
-		_ = unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+	if status.ManifestDigests != nil {
+		src.digests = addon.Digests{CRDs: string(status.ManifestDigests.CRDs), Manifest: string(status.ManifestDigests.Manifest)}
 	}
+	return src
 }
 
-func fetchAddonObjs(r *fsm, version, namespace string, istioInjection bool) ([]unstructured.Unstructured, error) {
-	objs, err := addon.FetchResources(r.HTTPClient, version)
+// fetchAddonObjs returns the add-on manifest of the given version patched for the namespace together with the extra resources
+func fetchAddonObjs(ctx context.Context, r *fsm, s *systemState, a Addon, src addonSource, version, namespace string) ([]unstructured.Unstructured, error) {
+	objs, err := a.Manifest(ctx, r, src, version)
 	if err != nil {
 		return nil, err
 	}
-	overrideNamespace(objs, namespace, istioInjection)
-	objs = append(objs, addon.NetworkPolicies(namespace)...)
-	objs = attachIstioAddonResources(objs, namespace, istioInjection)
-	return objs, nil
-}
-
-func attachIstioAddonResources(objs []unstructured.Unstructured, namespace string, istioInjection bool) []unstructured.Unstructured {
-	if istioInjection {
-		objs = append(objs, addon.PeerAuthentication(namespace))
-	}
-	return objs
+	a.Patch(objs, &s.instance, namespace)
+	return append(objs, a.ExtraResources(&s.instance, namespace)...), nil
 }
 
-func deletePeerAuthentication(ctx context.Context, r *fsm, namespace string) error {
-	obj := addon.PeerAuthentication(namespace)
-	err := r.Delete(ctx, &obj)
-	if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
-		return nil
+// deleteOptionalAddonResources removes the optional resources of the add-on from the namespace unless they are installed
+func deleteOptionalAddonResources(ctx context.Context, r *fsm, a Addon, namespace string, installed []unstructured.Unstructured) error {
+	var delErr error
+	for _, obj := range inventoryObjs(staleInventory(buildInventory(a.OptionalResources(namespace)), installed)) {
+		err := r.Delete(ctx, &obj)
+		if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+			continue
+		}
+		delErr = errors.Join(delErr, err)
 	}
-	return err
+	return delErr
 }
 
 func applyObjects(ctx context.Context, r *fsm, objs []unstructured.Unstructured) error {
@@ -243,217 +127,204 @@ func deleteObjects(ctx context.Context, r *fsm, objs []unstructured.Unstructured
 }
 
 func sFnHandleAddon(_ context.Context, _ *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	cfg := v1alpha1.ReadAddonCfg(&s.instance)
-	if !cfg.Enabled {
+	if !s.addon.Config(&s.instance).Enabled {
 		return switchState(sFnGuardAddonInUse)
 	}
 	return switchState(sFnApplyAddon)
 }
 
-// httpScaledObjectsInUse returns the number of HTTPScaledObjects on the
-// cluster. A missing CRD (NoKindMatchError / IsNotFound) returns (0, nil) —
-// there is nothing to block because the type doesn't exist. RBAC / API errors
-// are returned to the caller so they can surface a Warning and requeue.
-func httpScaledObjectsInUse(ctx context.Context, c client.Client) (int, error) {
-	list := &unstructured.UnstructuredList{}
-	list.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   httpScaledObjectGroup,
-		Version: httpScaledObjectVersion,
-		Kind:    httpScaledObjectKind + "List",
-	})
-	if err := c.List(ctx, list); err != nil {
-		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
-			return 0, nil
-		}
-		return 0, fmt.Errorf("list HTTPScaledObjects: %w", err)
-	}
-	return len(list.Items), nil
-}
-
-// sFnGuardAddonInUse blocks the disable path of the HTTP add-on while at least
-// one HTTPScaledObject exists on the cluster. The user must remove their
-// HTTPScaledObjects before the add-on can be uninstalled — otherwise removing
+// sFnGuardAddonInUse blocks the disable path of the add-on while at least one
+// resource depending on it exists on the cluster (e.g. HTTPScaledObjects for the HTTP add-on).
+// The user must remove them before the add-on can be uninstalled — otherwise removing
 // the CRD would leave orphaned resources of an unknown type.
 func sFnGuardAddonInUse(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	count, err := httpScaledObjectsInUse(ctx, r.Client)
+	a := s.addon
+	inUse, err := a.InUse(ctx, r.Client)
 	if err != nil {
-		msg := fmt.Sprintf("Cannot verify HTTPScaledObject usage: %v", err)
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse,
-			v1alpha1.ConditionReasonAddonInUse, msg)
+		msg := fmt.Sprintf("Cannot verify usage of the %s: %v", a.Title(), err)
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInUse, msg)
 		s.instance.Status.State = v1alpha1.StateWarning
-		return stopWithRequeueAfter(guardAddonInUseRequeue)
+		return stopAddonWithRequeueAfter(s, guardAddonInUseRequeue)
 	}
-	if count == 0 {
+	if len(inUse) == 0 {
 		return switchState(sFnDeleteAddon)
 	}
-	msg := fmt.Sprintf(
-		"%d HTTPScaledObject(s) still exist on the cluster; delete them before disabling the HTTP add-on",
-		count)
-	v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse,
-		v1alpha1.ConditionReasonAddonInUse, msg)
+	msg := fmt.Sprintf("%s still exist on the cluster; delete them before disabling the %s", countByKind(inUse), a.Title())
+	setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInUse, msg)
 	s.instance.Status.State = v1alpha1.StateWarning
-	return stopWithRequeueAfter(guardAddonInUseRequeue)
+	return stopAddonWithRequeueAfter(s, guardAddonInUseRequeue)
+}
+
+// countByKind describes the number of objects per kind, e.g. "2 HTTPScaledObject(s)"
+func countByKind(objs []unstructured.Unstructured) string {
+	counts := map[string]int{}
+	for _, obj := range objs {
+		counts[obj.GetKind()]++
+	}
+	described := make([]string, 0, len(counts))
+	for kind, count := range counts {
+		described = append(described, fmt.Sprintf("%d %s(s)", count, kind))
+	}
+	sort.Strings(described)
+	return strings.Join(described, ", ")
 }
 
 func sFnApplyAddon(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	cfg := v1alpha1.ReadAddonCfg(&s.instance)
-	version := v1alpha1.DefaultAddonVersion
-	targetNS := cfg.EffectiveNamespace()
+	a := s.addon
+	cfg := a.Config(&s.instance)
+	installed := installedAddon(&s.instance, a)
 
-	ann := s.instance.GetAnnotations()
-	prevVersion, prevNS := "", ""
-	if ann != nil {
-		prevVersion = ann[v1alpha1.AnnotationAddonInstalledVersion]
-		prevNS = ann[v1alpha1.AnnotationAddonInstalledNamespace]
+	version, err := a.ResolveVersion(r, cfg.Version, installed.Version)
+	if err != nil {
+		r.log.With("err", err).Errorf("failed to resolve %s version", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddon()
 	}
 
-	if err := ensureNamespace(ctx, r, targetNS); err != nil {
-		r.log.With("err", err).Error("failed to ensure addon namespace")
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, err.Error())
-		return stopWithNoRequeue()
+	if err := ensureNamespace(ctx, r, cfg.Namespace); err != nil {
+		r.log.With("err", err).Errorf("failed to ensure %s namespace", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddon()
 	}
 
-	if prevVersion != "" {
-		switch {
-		case prevNS != "" && prevNS != targetNS:
-			r.log.Infof("addon namespace changed %s -> %s, removing old resources", prevNS, targetNS)
-			cleanupOldAddon(ctx, r, prevVersion, prevNS)
-		case prevVersion != version:
-			r.log.Infof("addon version changed %s -> %s, removing old resources", prevVersion, version)
-			cleanupOldAddon(ctx, r, prevVersion, targetNS)
+	namespace, src := cfg.Namespace, cfg.Source
+	if upgrade := trackAddonUpgrade(&installed, version, namespace); upgrade != nil {
+		s.instance.SetAddonStatus(installed)
+		if upgrade.RolledBack {
+			// keep the restored installation until the add-on configuration changes
+			version, namespace = upgrade.FromVersion, upgrade.FromNamespace
+			src = addonSourceFromStatus(&s.instance, upgrade.FromSource)
 		}
 	}
 
-	r.log.Infof("fetching HTTP add-on resources for version %s", version)
-	objs, err := fetchAddonObjs(r, version, targetNS, cfg.IstioInjection)
+	r.log.Infof("fetching %s resources for version %s", a.Title(), version)
+	objs, err := fetchAddonObjs(ctx, r, s, a, src, version, namespace)
 	if err != nil {
-		r.log.With("err", err).Error("failed to fetch addon resources")
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, err.Error())
-		return stopWithNoRequeue()
+		r.log.With("err", err).Errorf("failed to fetch %s resources", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, addonFetchErrReason(err), err.Error())
+		return stopAddon()
 	}
 
 	if applyErr := applyObjects(ctx, r, objs); applyErr != nil {
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, applyErr.Error())
-		return stopWithNoRequeue()
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, applyErr.Error())
+		return stopAddon()
 	}
 
-	if !cfg.IstioInjection {
-		if err := deletePeerAuthentication(ctx, r, targetNS); err != nil {
-			r.log.With("err", err).Error("failed to delete addon PeerAuthentication after disabling Istio injection")
-			v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, err.Error())
-			return stopWithNoRequeue()
-		}
+	if err := deleteOptionalAddonResources(ctx, r, a, namespace, objs); err != nil {
+		r.log.With("err", err).Errorf("failed to delete optional %s resources", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddon()
 	}
 
-	r.AddonObjs = objs
-	v1alpha1.SetAnnotation(&s.instance, v1alpha1.AnnotationAddonInstalledVersion, version)
-	v1alpha1.SetAnnotation(&s.instance, v1alpha1.AnnotationAddonInstalledNamespace, targetNS)
-	v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionTrue, v1alpha1.ConditionReasonAddonInstalled,
-		fmt.Sprintf("HTTP add-on v%s installed in namespace %s", version, targetNS))
-
-	// Save desired status before r.Update, because r.Update overwrites s.instance
-	// with the server response which contains the OLD status from the server
-	// (status subresource is not updated by a regular Update call).
-	desiredStatus := s.instance.Status.DeepCopy()
+	persistAddonInstallation(ctx, r, s, a, installed, objs, src, version, namespace)
+	return switchState(sFnVerifyAddon)
+}
 
-	// Persist annotations so next reconcile knows the installed version/namespace.
-	if err := r.Update(ctx, &s.instance); err != nil {
-		r.log.With("err", err).Error("failed to persist addon annotations after install")
+// addonFetchErrReason returns the condition reason for the error returned while loading the add-on manifests
+func addonFetchErrReason(err error) string {
+	if errors.Is(err, addon.ErrDigestMismatch) || errors.Is(err, addon.ErrDigestNotPinned) {
+		return v1alpha1.AddonReasonDigestErr
 	}
-
-	// Restore desired status after Update (server response overwrites in-memory status).
-	s.instance.Status = *desiredStatus
-
-	r.log.Infof("HTTP add-on v%s installed in namespace %s", version, targetNS)
-	return stopWithNoRequeue()
+	return v1alpha1.AddonReasonInstallErr
 }
 
 func sFnDeleteAddon(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	objs := r.AddonObjs
+	a := s.addon
+	installed := installedAddon(&s.instance, a)
+	objs := installedAddonObjs(r, s, a)
 
-	ann := s.instance.GetAnnotations()
-	lastVersion, lastNS := "", v1alpha1.DefaultAddonNamespace
-	if ann != nil {
-		lastVersion = ann[v1alpha1.AnnotationAddonInstalledVersion]
-		if ns := ann[v1alpha1.AnnotationAddonInstalledNamespace]; ns != "" {
-			lastNS = ns
-		}
+	namespace := installed.Namespace
+	if namespace == "" {
+		namespace = a.Config(&s.instance).Namespace
 	}
 
 	if len(objs) == 0 {
-		if lastVersion == "" {
-			v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonDisabled, "HTTP add-on is disabled")
-			return stopWithNoRequeue()
+		if installed.Version == "" {
+			setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonDisabled,
+				withDeprecationNotice(s, a, fmt.Sprintf("%s is disabled", a.Title())))
+			return stopAddon()
 		}
-		r.log.Infof("re-fetching manifest for version %s to delete from namespace %s", lastVersion, lastNS)
+		// add-on installed before the inventory was introduced
+		r.log.Infof("re-fetching %s manifest for version %s to delete from namespace %s", a.Title(), installed.Version, namespace)
+		src := a.Config(&s.instance).Source
+		src.toDelete = true
 		var err error
-		objs, err = fetchAddonObjs(r, lastVersion, lastNS, false)
+		objs, err = fetchAddonObjs(ctx, r, s, a, src, installed.Version, namespace)
 		if err != nil {
-			r.log.With("err", err).Error("failed to re-fetch addon manifest for deletion")
-			v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonDeleted, err.Error())
-			return stopWithNoRequeue()
+			r.log.With("err", err).Errorf("failed to re-fetch %s manifest for deletion", a.Title())
+			setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonDeleted, err.Error())
+			return stopAddon()
 		}
 	}
 
 	if delErr := deleteObjects(ctx, r, objs); delErr != nil {
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, delErr.Error())
-		return stopWithNoRequeue()
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, delErr.Error())
+		return stopAddon()
 	}
-	if err := deletePeerAuthentication(ctx, r, lastNS); err != nil {
-		v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonInstallErr, err.Error())
-		return stopWithNoRequeue()
+	if err := deleteOptionalAddonResources(ctx, r, a, namespace, nil); err != nil {
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddon()
 	}
 
-	r.AddonObjs = nil
-	v1alpha1.SetAnnotation(&s.instance, v1alpha1.AnnotationAddonInstalledVersion, "")
-	v1alpha1.SetAnnotation(&s.instance, v1alpha1.AnnotationAddonInstalledNamespace, "")
-	v1alpha1.SetAddonCondition(&s.instance, metav1.ConditionFalse, v1alpha1.ConditionReasonAddonDeleted, "HTTP add-on removed")
-
-	// Save desired status before r.Update, because r.Update overwrites s.instance
-	// with the server response which contains the OLD status from the server
-	// (status subresource is not updated by a regular Update call).
-	desiredStatus := s.instance.Status.DeepCopy()
+	delete(r.AddonObjs, a.Name())
+	recordAddonStatus(ctx, r, s, a, nil)
+	setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonDeleted, fmt.Sprintf("%s removed", a.Title()))
+	r.log.Infof("%s removed", a.Title())
+	return stopAddon()
+}
 
-	// Persist annotation removal on the CR.
-	if err := r.Update(ctx, &s.instance); err != nil {
-		r.log.With("err", err).Error("failed to persist addon annotations after delete")
+// installedAddonObjs returns the add-on objects applied in this reconciliation or listed in the inventory,
+// together with the objects of the previous installation if an upgrade is in progress
+func installedAddonObjs(r *fsm, s *systemState, a Addon) []unstructured.Unstructured {
+	installed := installedAddon(&s.instance, a)
+	objs := append([]unstructured.Unstructured{}, r.AddonObjs[a.Name()]...)
+	if len(objs) == 0 {
+		objs = inventoryObjs(installed.Inventory)
 	}
-
-	// Restore desired status after Update (server response overwrites in-memory status).
-	s.instance.Status = *desiredStatus
-
-	r.log.Info("HTTP add-on removed")
-	return stopWithNoRequeue()
+	if installed.Upgrade != nil {
+		objs = append(objs, inventoryObjs(installed.Upgrade.PreviousInventory)...)
+	}
+	return objs
 }
 
-func cleanupOldAddon(ctx context.Context, r *fsm, version, namespace string) {
-	objs, err := fetchAddonObjs(r, version, namespace, false)
-	if err != nil {
-		r.log.With("err", err).Warn("failed to fetch old addon manifest for cleanup")
-		return
+func inventoryObjs(inventory []v1alpha1.InventoryEntry) []unstructured.Unstructured {
+	objs := make([]unstructured.Unstructured, 0, len(inventory))
+	for _, entry := range inventory {
+		objs = append(objs, inventoryObj(entry))
 	}
-	_ = deleteObjects(ctx, r, objs)
-	_ = deletePeerAuthentication(ctx, r, namespace)
+	return objs
 }
 
-func deleteAddonObjs(ctx context.Context, r *fsm) error {
+// deleteAddonObjs removes the resources of all registered add-ons, the optional add-on resources
+// are removed from every namespace the add-on may have been installed in
+func deleteAddonObjs(ctx context.Context, r *fsm, s *systemState, filterFunc ...filterFunc) error {
 	var delErr error
-	namespaces := map[string]struct{}{v1alpha1.DefaultAddonNamespace: {}}
-	for _, obj := range r.AddonObjs {
-		o := unstructured.Unstructured{}
-		o.SetGroupVersionKind(obj.GroupVersionKind())
-		o.SetName(obj.GetName())
-		o.SetNamespace(obj.GetNamespace())
-		if ns := obj.GetNamespace(); ns != "" {
+	for _, a := range r.Addons {
+		namespaces := map[string]struct{}{
+			a.Config(&s.instance).Namespace: {},
+		}
+		if ns := installedAddon(&s.instance, a).Namespace; ns != "" {
 			namespaces[ns] = struct{}{}
 		}
-		if err := r.Delete(ctx, &o); client.IgnoreNotFound(err) != nil {
-			delErr = errors.Join(delErr, err)
+		for _, obj := range installedAddonObjs(r, s, a) {
+			if !fitToFilters(obj, filterFunc...) {
+				continue
+			}
+			o := unstructured.Unstructured{}
+			o.SetGroupVersionKind(obj.GroupVersionKind())
+			o.SetName(obj.GetName())
+			o.SetNamespace(obj.GetNamespace())
+			if ns := obj.GetNamespace(); ns != "" {
+				namespaces[ns] = struct{}{}
+			}
+			if err := r.Delete(ctx, &o); client.IgnoreNotFound(err) != nil {
+				delErr = errors.Join(delErr, err)
+			}
 		}
-	}
-	for ns := range namespaces {
-		if err := deletePeerAuthentication(ctx, r, ns); err != nil {
-			delErr = errors.Join(delErr, err)
+		for ns := range namespaces {
+			if err := deleteOptionalAddonResources(ctx, r, a, ns, nil); err != nil {
+				delErr = errors.Join(delErr, err)
+			}
 		}
 	}
 	return delErr
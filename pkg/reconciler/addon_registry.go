@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Addon is a KEDA ecosystem component installed by keda-manager next to KEDA;
+// every add-on is enabled and tracked in the Keda status independently
+type Addon interface {
+	// Name identifies the add-on in status.addons
+	Name() string
+	// Title is used in the condition messages, e.g. "HTTP add-on"
+	Title() string
+	// ConditionType is the type of the condition reporting the add-on installation
+	ConditionType() v1alpha1.ConditionType
+	// ReasonPrefix is prepended to the add-on condition reasons
+	ReasonPrefix() string
+	// Config reads the add-on configuration from the Keda CR
+	Config(instance *v1alpha1.Keda) AddonConfig
+	// ResolveVersion returns the add-on version to install for the configured one
+	ResolveVersion(r *fsm, version, installedVersion string) (string, error)
+	// Manifest returns the add-on resources of the given version read from the source
+	Manifest(ctx context.Context, r *fsm, src addonSource, version string) ([]unstructured.Unstructured, error)
+	// Patch adjusts the add-on resources to the namespace and the configuration of the Keda CR
+	Patch(objs []unstructured.Unstructured, instance *v1alpha1.Keda, namespace string)
+	// ExtraResources returns the resources installed together with the add-on manifest
+	ExtraResources(instance *v1alpha1.Keda, namespace string) []unstructured.Unstructured
+	// OptionalResources returns the extra resources that are installed depending on the configuration,
+	// they are removed from the namespace when they are not part of the installation
+	OptionalResources(namespace string) []unstructured.Unstructured
+	// InUse returns the user resources that depend on the add-on and block its removal
+	InUse(ctx context.Context, c client.Client) ([]unstructured.Unstructured, error)
+	// NotReady returns the add-on components that are not ready yet
+	NotReady(objs []unstructured.Unstructured) ([]string, error)
+}
+
+// AddonConfig is the add-on configuration common to all add-ons
+type AddonConfig struct {
+	Enabled bool
+	// Namespace is the namespace the add-on is installed in
+	Namespace string
+	// Version is the configured version, it is resolved by the add-on
+	Version string
+	// Source is the add-on manifests source configured in the Keda CR
+	Source addonSource
+}
+
+// DefaultAddons returns the add-ons managed by keda-manager in the order they are reconciled
+func DefaultAddons() []Addon {
+	return []Addon{httpAddon{}}
+}
+
+// legacyStatusAddon is implemented by the add-ons which recorded the installation outside of status.addons
+type legacyStatusAddon interface {
+	// legacyStatus returns the installation recorded in the Keda CR, nil if there is none
+	legacyStatus(instance *v1alpha1.Keda) *v1alpha1.AddonStatus
+	// clearLegacyStatus removes the recorded installation and returns true if the Keda CR was changed
+	clearLegacyStatus(instance *v1alpha1.Keda) bool
+}
+
+// deprecatedConfigAddon is implemented by the add-ons which still honour a deprecated configuration
+type deprecatedConfigAddon interface {
+	// deprecationNotice describes the deprecated configuration used in the Keda CR, empty if there is none
+	deprecationNotice(instance *v1alpha1.Keda) string
+}
+
+// withDeprecationNotice appends the notice about the deprecated add-on configuration used in the Keda CR to the message
+func withDeprecationNotice(s *systemState, a Addon, msg string) string {
+	deprecated, ok := a.(deprecatedConfigAddon)
+	if !ok {
+		return msg
+	}
+	if notice := deprecated.deprecationNotice(&s.instance); notice != "" {
+		return fmt.Sprintf("%s; %s", msg, notice)
+	}
+	return msg
+}
+
+// installedAddon returns the installation of the add-on recorded in the Keda CR,
+// an empty status with the add-on name is returned if the add-on is not installed
+func installedAddon(instance *v1alpha1.Keda, a Addon) v1alpha1.AddonStatus {
+	if status := instance.GetAddonStatus(a.Name()); status != nil {
+		return *status.DeepCopy()
+	}
+	if legacy, ok := a.(legacyStatusAddon); ok {
+		if status := legacy.legacyStatus(instance); status != nil {
+			return *status
+		}
+	}
+	return v1alpha1.AddonStatus{Name: a.Name()}
+}
+
+// recordAddonStatus stores the add-on installation in status.addons and drops the legacy records
+func recordAddonStatus(ctx context.Context, r *fsm, s *systemState, a Addon, status *v1alpha1.AddonStatus) {
+	if status != nil {
+		s.instance.SetAddonStatus(*status)
+	} else {
+		s.instance.RemoveAddonStatus(a.Name())
+	}
+
+	legacy, ok := a.(legacyStatusAddon)
+	if !ok || !legacy.clearLegacyStatus(&s.instance) {
+		return
+	}
+
+	// Save desired status before r.Update, because r.Update overwrites s.instance
+	// with the server response which contains the OLD status from the server
+	// (status subresource is not updated by a regular Update call).
+	desiredStatus := s.instance.Status.DeepCopy()
+
+	if err := r.Update(ctx, &s.instance); err != nil {
+		r.log.With("err", err).Errorf("failed to remove legacy %s annotations", a.Title())
+	}
+
+	// Restore desired status after Update (server response overwrites in-memory status).
+	s.instance.Status = *desiredStatus
+}
+
+func setAddonCondition(s *systemState, a Addon, status metav1.ConditionStatus, reason, msg string) {
+	s.instance.UpdateCondition(a.ConditionType(), status,
+		v1alpha1.ConditionReason(a.ReasonPrefix()+reason), msg)
+}
+
+// sFnHandleAddons reconciles the registered add-ons one after another
+// and stops with the earliest requeue requested by them
+func sFnHandleAddons(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	if s.addonIndex >= len(r.Addons) {
+		return sFnUpdateStatus(s.addonsResult, nil), nil, nil
+	}
+	s.addon = r.Addons[s.addonIndex]
+	s.addonIndex++
+	return switchState(sFnHandleAddon)
+}
+
+// stopAddon ends the reconciliation of the current add-on and continues with the next one
+func stopAddon() (stateFn, *ctrl.Result, error) {
+	return switchState(sFnHandleAddons)
+}
+
+// stopAddonWithRequeueAfter ends the reconciliation of the current add-on and requeues
+// the reconciliation once all add-ons are handled
+func stopAddonWithRequeueAfter(s *systemState, duration time.Duration) (stateFn, *ctrl.Result, error) {
+	if s.addonsResult == nil || s.addonsResult.RequeueAfter > duration {
+		s.addonsResult = &ctrl.Result{RequeueAfter: duration}
+	}
+	return switchState(sFnHandleAddons)
+}
@@ -0,0 +1,142 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// otherAddon is a second add-on sharing the HTTP add-on implementation
+type otherAddon struct {
+	httpAddon
+}
+
+func (otherAddon) Name() string {
+	return "other"
+}
+
+func (otherAddon) Title() string {
+	return "other add-on"
+}
+
+func (otherAddon) ConditionType() v1alpha1.ConditionType {
+	return "OtherAddonInstalled"
+}
+
+func (otherAddon) ReasonPrefix() string {
+	return "Other"
+}
+
+func Test_sFnHandleAddons(t *testing.T) {
+	t.Run("no add-ons registered", func(t *testing.T) {
+		next, result, err := sFnHandleAddons(context.Background(), &fsm{}, &systemState{})
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+	})
+	t.Run("reconcile add-ons one by one", func(t *testing.T) {
+		r := &fsm{Cfg: Cfg{Addons: []Addon{httpAddon{}, otherAddon{}}}}
+		s := &systemState{}
+
+		next, _, _ := sFnHandleAddons(context.Background(), r, s)
+		requireEqualFunc(t, sFnHandleAddon, next)
+		require.Equal(t, httpAddonName, s.addon.Name())
+
+		next, _, _ = sFnHandleAddons(context.Background(), r, s)
+		requireEqualFunc(t, sFnHandleAddon, next)
+		require.Equal(t, "other", s.addon.Name())
+
+		next, _, _ = sFnHandleAddons(context.Background(), r, s)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+	})
+	t.Run("add-ons are tracked independently", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: fake.NewClientBuilder().Build()},
+			Cfg: Cfg{Addons: []Addon{httpAddon{}, otherAddon{}}},
+		}
+		s := &systemState{instance: v1alpha1.Keda{Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{
+			{
+				Name:      "other",
+				Version:   "0.15.0",
+				Namespace: "kyma-system",
+				Inventory: buildInventory([]unstructured.Unstructured{fixAddonService("other-add-on")}),
+			},
+		}}}}
+
+		// both add-ons are disabled, only the installed one is removed
+		next := stateFn(sFnHandleAddons)
+		for s.addonIndex < len(r.Addons) || getFnName(next) != getFnName(sFnHandleAddons) {
+			next, _, _ = next(context.Background(), r, s)
+		}
+		next, _, _ = next(context.Background(), r, s)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+
+		httpCondition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.Equal(t, v1alpha1.ConditionReasonAddonDisabled, httpCondition.Reason)
+		otherCondition := meta.FindStatusCondition(s.instance.Status.Conditions, "OtherAddonInstalled")
+		require.Equal(t, "Other"+v1alpha1.AddonReasonDeleted, otherCondition.Reason)
+		require.Equal(t, "other add-on removed", otherCondition.Message)
+		require.Empty(t, s.instance.Status.Addons)
+	})
+}
+
+func Test_stopAddonWithRequeueAfter(t *testing.T) {
+	s := &systemState{}
+
+	next, result, err := stopAddonWithRequeueAfter(s, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	requireEqualFunc(t, sFnHandleAddons, next)
+
+	_, _, _ = stopAddonWithRequeueAfter(s, 10*time.Second)
+	_, _, _ = stopAddonWithRequeueAfter(s, time.Minute)
+	require.Equal(t, &ctrl.Result{RequeueAfter: 10 * time.Second}, s.addonsResult)
+}
+
+func Test_installedAddon(t *testing.T) {
+	legacyInstance := v1alpha1.Keda{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			v1alpha1.AnnotationAddonInstalledVersion:   "0.14.0",
+			v1alpha1.AnnotationAddonInstalledNamespace: "kyma-system",
+		}},
+	}
+
+	t.Run("not installed", func(t *testing.T) {
+		require.Equal(t, v1alpha1.AddonStatus{Name: httpAddonName}, installedAddon(&v1alpha1.Keda{}, httpAddon{}))
+	})
+	t.Run("installation recorded in status.addons", func(t *testing.T) {
+		instance := legacyInstance.DeepCopy()
+		instance.SetAddonStatus(v1alpha1.AddonStatus{Name: httpAddonName, Version: "0.15.0", Namespace: "keda"})
+
+		installed := installedAddon(instance, httpAddon{})
+		require.Equal(t, "0.15.0", installed.Version)
+		require.Equal(t, "keda", installed.Namespace)
+	})
+	t.Run("installation recorded in the legacy annotations", func(t *testing.T) {
+		require.Equal(t, v1alpha1.AddonStatus{
+			Name:      httpAddonName,
+			Version:   "0.14.0",
+			Namespace: "kyma-system",
+		}, installedAddon(&legacyInstance, httpAddon{}))
+	})
+	t.Run("legacy records are dropped once the installation is recorded", func(t *testing.T) {
+		r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: fake.NewClientBuilder().Build()}}
+		s := &systemState{instance: *legacyInstance.DeepCopy()}
+
+		installed := installedAddon(&s.instance, httpAddon{})
+		recordAddonStatus(context.Background(), r, s, httpAddon{}, &installed)
+
+		require.Empty(t, s.instance.GetAnnotations())
+		require.Equal(t, []v1alpha1.AddonStatus{installed}, s.instance.Status.Addons)
+	})
+}
@@ -2,17 +2,28 @@ package reconciler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	"github.com/kyma-project/keda-manager/pkg/addon"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -74,6 +85,46 @@ func TestReadAddonCfg(t *testing.T) {
 			require.Equal(t, tt.want, v1alpha1.ReadAddonCfg(instance))
 		})
 	}
+	t.Run("spec overrides deprecated annotations", func(t *testing.T) {
+		instance := &v1alpha1.Keda{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				v1alpha1.AnnotationAddonEnabled:        "true",
+				v1alpha1.AnnotationAddonNamespace:      "annotation-ns",
+				v1alpha1.AnnotationAddonIstioInjection: "true",
+			}},
+			Spec: v1alpha1.KedaSpec{HTTPAddon: &v1alpha1.HTTPAddon{
+				Enabled:   ptr.To(false),
+				Namespace: "spec-ns",
+			}},
+		}
+		require.Equal(t, v1alpha1.AddonCfg{Enabled: false, Namespace: "spec-ns", IstioInjection: true}, v1alpha1.ReadAddonCfg(instance))
+		require.Len(t, v1alpha1.DeprecatedAddonAnnotations(instance), 3)
+	})
+	t.Run("settings from spec", func(t *testing.T) {
+		instance := &v1alpha1.Keda{
+			Spec: v1alpha1.KedaSpec{HTTPAddon: &v1alpha1.HTTPAddon{
+				Enabled: ptr.To(true),
+				Istio:   &v1alpha1.IstioCfg{EnabledSidecarInjection: true},
+			}},
+		}
+		require.Equal(t, v1alpha1.AddonCfg{Enabled: true, IstioInjection: true}, v1alpha1.ReadAddonCfg(instance))
+		require.Empty(t, v1alpha1.DeprecatedAddonAnnotations(instance))
+	})
+	t.Run("version from spec", func(t *testing.T) {
+		instance := &v1alpha1.Keda{
+			Spec: v1alpha1.KedaSpec{HTTPAddon: &v1alpha1.HTTPAddon{Version: v1alpha1.AddonVersionLatest}},
+		}
+		require.Equal(t, v1alpha1.AddonCfg{Version: v1alpha1.AddonVersionLatest}, v1alpha1.ReadAddonCfg(instance))
+	})
+	t.Run("manifest digests from spec", func(t *testing.T) {
+		instance := &v1alpha1.Keda{
+			Spec: v1alpha1.KedaSpec{HTTPAddon: &v1alpha1.HTTPAddon{ManifestDigests: &v1alpha1.AddonManifestDigests{
+				CRDs:     "crds-digest",
+				Manifest: "manifest-digest",
+			}}},
+		}
+		require.Equal(t, v1alpha1.AddonCfg{CRDsDigest: "crds-digest", ManifestDigest: "manifest-digest"}, v1alpha1.ReadAddonCfg(instance))
+	})
 }
 
 func TestEffectiveNamespace(t *testing.T) {
@@ -481,7 +532,7 @@ func TestPatchDeploymentEnvNamespace(t *testing.T) {
 
 func TestSFnHandleAddon(t *testing.T) {
 	t.Run("disabled addon switches to guard", func(t *testing.T) {
-		s := &systemState{instance: v1alpha1.Keda{ObjectMeta: metav1.ObjectMeta{
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{v1alpha1.AnnotationAddonEnabled: "false"},
 		}}}
 		fn, result, err := sFnHandleAddon(context.TODO(), nil, s)
@@ -490,7 +541,7 @@ func TestSFnHandleAddon(t *testing.T) {
 		requireEqualFunc(t, sFnGuardAddonInUse, fn)
 	})
 	t.Run("enabled addon switches to apply", func(t *testing.T) {
-		s := &systemState{instance: v1alpha1.Keda{ObjectMeta: metav1.ObjectMeta{
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{v1alpha1.AnnotationAddonEnabled: "true"},
 		}}}
 		fn, result, err := sFnHandleAddon(context.TODO(), nil, s)
@@ -512,42 +563,42 @@ func newHTTPScaledObject(namespace, name string) *unstructured.Unstructured {
 	return hso
 }
 
-func TestHTTPScaledObjectsInUse(t *testing.T) {
-	t.Run("returns zero when no HTTPScaledObjects exist", func(t *testing.T) {
+func TestHTTPAddonInUse(t *testing.T) {
+	t.Run("returns nothing when no HTTPScaledObjects exist", func(t *testing.T) {
 		c := fake.NewClientBuilder().Build()
-		count, err := httpScaledObjectsInUse(context.Background(), c)
+		inUse, err := httpAddon{}.InUse(context.Background(), c)
 		require.NoError(t, err)
-		require.Equal(t, 0, count)
+		require.Empty(t, inUse)
 	})
-	t.Run("counts all HTTPScaledObjects across namespaces", func(t *testing.T) {
+	t.Run("returns all HTTPScaledObjects across namespaces", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithObjects(
 			newHTTPScaledObject("ns-a", "foo"),
 			newHTTPScaledObject("ns-a", "bar"),
 			newHTTPScaledObject("ns-b", "baz"),
 		).Build()
-		count, err := httpScaledObjectsInUse(context.Background(), c)
+		inUse, err := httpAddon{}.InUse(context.Background(), c)
 		require.NoError(t, err)
-		require.Equal(t, 3, count)
+		require.Len(t, inUse, 3)
 	})
-	t.Run("treats NoKindMatchError as zero", func(t *testing.T) {
+	t.Run("treats NoKindMatchError as not in use", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
 			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
 				return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: httpScaledObjectGroup, Kind: httpScaledObjectKind}}
 			},
 		}).Build()
-		count, err := httpScaledObjectsInUse(context.Background(), c)
+		inUse, err := httpAddon{}.InUse(context.Background(), c)
 		require.NoError(t, err)
-		require.Equal(t, 0, count)
+		require.Empty(t, inUse)
 	})
-	t.Run("treats IsNotFound as zero", func(t *testing.T) {
+	t.Run("treats IsNotFound as not in use", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
 			List: func(_ context.Context, _ client.WithWatch, _ client.ObjectList, _ ...client.ListOption) error {
 				return apierrors.NewNotFound(schema.GroupResource{Group: httpScaledObjectGroup, Resource: "httpscaledobjects"}, "")
 			},
 		}).Build()
-		count, err := httpScaledObjectsInUse(context.Background(), c)
+		inUse, err := httpAddon{}.InUse(context.Background(), c)
 		require.NoError(t, err)
-		require.Equal(t, 0, count)
+		require.Empty(t, inUse)
 	})
 	t.Run("surfaces other list errors (fail-closed)", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
@@ -555,7 +606,7 @@ func TestHTTPScaledObjectsInUse(t *testing.T) {
 				return apierrors.NewForbidden(schema.GroupResource{Group: httpScaledObjectGroup, Resource: "httpscaledobjects"}, "", errors.New("rbac"))
 			},
 		}).Build()
-		_, err := httpScaledObjectsInUse(context.Background(), c)
+		_, err := httpAddon{}.InUse(context.Background(), c)
 		require.Error(t, err)
 		require.ErrorContains(t, err, "list HTTPScaledObjects")
 	})
@@ -567,7 +618,7 @@ func TestSFnGuardAddonInUse(t *testing.T) {
 	t.Run("no HTTPScaledObjects → switches to delete", func(t *testing.T) {
 		c := fake.NewClientBuilder().Build()
 		r := &fsm{K8s: K8s{Client: c}}
-		s := &systemState{instance: v1alpha1.Keda{}}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{}}
 
 		fn, result, err := sFnGuardAddonInUse(context.Background(), r, s)
 		require.NoError(t, err)
@@ -580,7 +631,7 @@ func TestSFnGuardAddonInUse(t *testing.T) {
 			newHTTPScaledObject("billing", "api"),
 		).Build()
 		r := &fsm{K8s: K8s{Client: c}}
-		s := &systemState{instance: v1alpha1.Keda{}}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{}}
 
 		_, _, err := sFnGuardAddonInUse(context.Background(), r, s)
 		require.NoError(t, err)
@@ -603,7 +654,7 @@ func TestSFnGuardAddonInUse(t *testing.T) {
 			},
 		}).Build()
 		r := &fsm{K8s: K8s{Client: c}}
-		s := &systemState{instance: v1alpha1.Keda{}}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{}}
 
 		_, _, err := sFnGuardAddonInUse(context.Background(), r, s)
 		require.NoError(t, err)
@@ -611,7 +662,7 @@ func TestSFnGuardAddonInUse(t *testing.T) {
 		var found bool
 		for _, c := range s.instance.Status.Conditions {
 			if c.Reason == v1alpha1.ConditionReasonAddonInUse {
-				require.Contains(t, c.Message, "Cannot verify HTTPScaledObject usage")
+				require.Contains(t, c.Message, "Cannot verify usage of the HTTP add-on")
 				found = true
 			}
 		}
@@ -629,22 +680,31 @@ func TestAttachIstioAddonResources(t *testing.T) {
 	})
 }
 
-func TestDeletePeerAuthentication(t *testing.T) {
+func TestDeleteOptionalAddonResources(t *testing.T) {
 	t.Run("deletes existing PeerAuthentication", func(t *testing.T) {
 		pa := addon.PeerAuthentication("kyma-system")
 		c := fake.NewClientBuilder().WithObjects(&pa).Build()
 		r := &fsm{K8s: K8s{Client: c}}
 
-		require.NoError(t, deletePeerAuthentication(context.Background(), r, "kyma-system"))
+		require.NoError(t, deleteOptionalAddonResources(context.Background(), r, httpAddon{}, "kyma-system", nil))
 
 		got := addon.PeerAuthentication("kyma-system")
 		err := c.Get(context.Background(), client.ObjectKey{Name: got.GetName(), Namespace: got.GetNamespace()}, &got)
 		require.True(t, apierrors.IsNotFound(err))
 	})
+	t.Run("keeps installed PeerAuthentication", func(t *testing.T) {
+		pa := addon.PeerAuthentication("kyma-system")
+		c := fake.NewClientBuilder().WithObjects(pa.DeepCopy()).Build()
+		r := &fsm{K8s: K8s{Client: c}}
+
+		require.NoError(t, deleteOptionalAddonResources(context.Background(), r, httpAddon{}, "kyma-system",
+			[]unstructured.Unstructured{pa}))
+		require.True(t, canGetFakeResource(c, pa))
+	})
 	t.Run("ignores missing PeerAuthentication", func(t *testing.T) {
 		c := fake.NewClientBuilder().Build()
 		r := &fsm{K8s: K8s{Client: c}}
-		require.NoError(t, deletePeerAuthentication(context.Background(), r, "kyma-system"))
+		require.NoError(t, deleteOptionalAddonResources(context.Background(), r, httpAddon{}, "kyma-system", nil))
 	})
 	t.Run("ignores missing PeerAuthentication CRD", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
@@ -653,7 +713,7 @@ func TestDeletePeerAuthentication(t *testing.T) {
 			},
 		}).Build()
 		r := &fsm{K8s: K8s{Client: c}}
-		require.NoError(t, deletePeerAuthentication(context.Background(), r, "kyma-system"))
+		require.NoError(t, deleteOptionalAddonResources(context.Background(), r, httpAddon{}, "kyma-system", nil))
 	})
 }
 
@@ -665,3 +725,313 @@ func hasPeerAuthentication(objs []unstructured.Unstructured) bool {
 	}
 	return false
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func fixTagsHTTPClient(status int, body string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(_ *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})}
+}
+
+func TestResolveAddonVersion(t *testing.T) {
+	t.Run("default version", func(t *testing.T) {
+		version, err := resolveAddonVersion(&fsm{}, "", "0.14.0")
+		require.NoError(t, err)
+		require.Equal(t, v1alpha1.DefaultAddonVersion, version)
+	})
+	t.Run("pinned version", func(t *testing.T) {
+		version, err := resolveAddonVersion(&fsm{}, "v0.16.1", "")
+		require.NoError(t, err)
+		require.Equal(t, "0.16.1", version)
+	})
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := resolveAddonVersion(&fsm{}, "newest", "")
+		require.Error(t, err)
+	})
+	t.Run("latest version", func(t *testing.T) {
+		r := &fsm{Cfg: Cfg{HTTPClient: fixTagsHTTPClient(http.StatusOK, `[{"name":"v0.17.0"},{"name":"v0.16.0"}]`)}}
+		version, err := resolveAddonVersion(r, v1alpha1.AddonVersionLatest, "")
+		require.NoError(t, err)
+		require.Equal(t, "0.17.0", version)
+	})
+	t.Run("keep installed version when latest can't be resolved", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{HTTPClient: fixTagsHTTPClient(http.StatusForbidden, "")},
+		}
+		version, err := resolveAddonVersion(r, v1alpha1.AddonVersionLatest, "0.15.0")
+		require.NoError(t, err)
+		require.Equal(t, "0.15.0", version)
+
+		_, err = resolveAddonVersion(r, v1alpha1.AddonVersionLatest, "")
+		require.Error(t, err)
+	})
+}
+
+func TestLoadAddonResources(t *testing.T) {
+	cmKey := client.ObjectKey{Namespace: "kyma-system", Name: "addon-manifests"}
+
+	t.Run("load manifests from ConfigMap", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cmKey.Namespace, Name: cmKey.Name},
+			Data: map[string]string{
+				"addon.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: keda-add-ons-http-interceptor-proxy\n",
+			},
+		}).Build()
+		r := &fsm{K8s: K8s{Client: c}}
+
+		objs, err := loadAddonResources(context.Background(), r, addonSource{configMap: cmKey}, "0.15.0")
+		require.NoError(t, err)
+		require.Len(t, objs, 1)
+		require.Equal(t, "keda-add-ons-http-interceptor-proxy", objs[0].GetName())
+	})
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		r := &fsm{K8s: K8s{Client: fake.NewClientBuilder().Build()}}
+
+		_, err := loadAddonResources(context.Background(), r, addonSource{configMap: cmKey}, "0.15.0")
+		require.Error(t, err)
+		require.True(t, apierrors.IsNotFound(errors.Unwrap(err)))
+	})
+}
+
+func TestAddonSourceFromCfg(t *testing.T) {
+	instance := &v1alpha1.Keda{ObjectMeta: metav1.ObjectMeta{Namespace: "kyma-system"}}
+
+	require.Equal(t, addonSource{}, addonSourceFromCfg(instance, v1alpha1.AddonCfg{}))
+	require.Equal(t,
+		addonSource{
+			configMap: client.ObjectKey{Namespace: "kyma-system", Name: "addon-manifests"},
+			digests:   addon.Digests{CRDs: "crds-digest", Manifest: "manifest-digest"},
+		},
+		addonSourceFromCfg(instance, v1alpha1.AddonCfg{
+			ManifestsConfigMap: "addon-manifests",
+			CRDsDigest:         "crds-digest",
+			ManifestDigest:     "manifest-digest",
+		}),
+	)
+}
+
+func TestAddonFetchErrReason(t *testing.T) {
+	require.Equal(t, v1alpha1.AddonReasonDigestErr,
+		addonFetchErrReason(fmt.Errorf("failed to fetch addon CRDs: %w", addon.ErrDigestMismatch)))
+	require.Equal(t, v1alpha1.AddonReasonInstallErr, addonFetchErrReason(errors.New("download failed")))
+}
+
+func TestSFnDeleteAddon_fromInventory(t *testing.T) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("kyma-system")
+	deployment.SetName("keda-add-ons-http-interceptor")
+
+	c := fake.NewClientBuilder().WithObjects(deployment).Build()
+	// no HTTP client, the add-on must be removed without downloading the manifest
+	r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}}
+	s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{
+		Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{{
+			Name:      httpAddonName,
+			Version:   "0.15.0",
+			Namespace: "kyma-system",
+			Inventory: buildInventory([]unstructured.Unstructured{*deployment}),
+		}}},
+	}}
+
+	_, _, err := sFnDeleteAddon(context.Background(), r, s)
+	require.NoError(t, err)
+	require.Nil(t, s.instance.GetAddonStatus(httpAddonName))
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(deployment), deployment.DeepCopy())
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestSFnDeleteAddon_legacyAnnotations(t *testing.T) {
+	// the add-on network policies are loaded relative to the repository root
+	t.Chdir("../..")
+
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("kyma-system")
+	deployment.SetName("keda-add-ons-http-interceptor")
+
+	c := fake.NewClientBuilder().WithObjects(deployment).Build()
+	// no digests are pinned for the version, the manifest is downloaded only to identify the installed objects
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: keda-add-ons-http-interceptor\n  namespace: keda\n"
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		K8s: K8s{Client: c},
+		Cfg: Cfg{HTTPClient: httpClient, AddonCache: addon.NewCache()},
+	}
+	s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			v1alpha1.AnnotationAddonInstalledVersion:   "0.99.0",
+			v1alpha1.AnnotationAddonInstalledNamespace: "kyma-system",
+		}},
+	}}
+
+	_, _, err := sFnDeleteAddon(context.Background(), r, s)
+	require.NoError(t, err)
+	condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+	require.Equal(t, v1alpha1.ConditionReasonAddonDeleted, condition.Reason)
+	require.Equal(t, "HTTP add-on removed", condition.Message)
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(deployment), deployment.DeepCopy())
+	require.True(t, apierrors.IsNotFound(err))
+	// the unverified manifest is not cached for installations
+	_, found := r.AddonCache.Get(httpAddonName + "/0.99.0//")
+	require.False(t, found)
+}
+
+func TestLoadAddonResources_cache(t *testing.T) {
+	var downloads int
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		downloads++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(fixDownloadedAsset(path.Base(req.URL.Path)))),
+		}, nil
+	})}
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		Cfg: Cfg{HTTPClient: httpClient, AddonCache: addon.NewCache()},
+	}
+
+	src := addonSource{digests: addon.Digests{
+		CRDs:     sha256Hex(fixDownloadedAsset("keda-add-ons-http-0.99.0-crds.yaml")),
+		Manifest: sha256Hex(fixDownloadedAsset("keda-add-ons-http-0.99.0.yaml")),
+	}}
+
+	objs, err := loadAddonResources(context.Background(), r, src, "0.99.0")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	require.Equal(t, 2, downloads)
+
+	objs, err = loadAddonResources(context.Background(), r, src, "0.99.0")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	require.Equal(t, 2, downloads)
+}
+
+func TestLoadAddonResources_notPinned(t *testing.T) {
+	var downloads int
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		downloads++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(fixDownloadedAsset(path.Base(req.URL.Path)))),
+		}, nil
+	})}
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		Cfg: Cfg{HTTPClient: httpClient, AddonCache: addon.NewCache()},
+	}
+
+	_, err := loadAddonResources(context.Background(), r, addonSource{}, "0.99.0")
+	require.ErrorIs(t, err, addon.ErrDigestNotPinned)
+	require.Equal(t, v1alpha1.AddonReasonDigestErr, addonFetchErrReason(err))
+	require.Zero(t, downloads)
+}
+
+func fixDownloadedAsset(name string) string {
+	return "apiVersion: v1\nkind: Service\nmetadata:\n  name: " + name + "\n"
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDeleteAddonObjs_filter(t *testing.T) {
+	crd := unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("httpscaledobjects.http.keda.sh")
+
+	c := fake.NewClientBuilder().WithObjects(crd.DeepCopy()).Build()
+	r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}, Cfg: Cfg{Addons: DefaultAddons()}}
+	s := &systemState{instance: v1alpha1.Keda{
+		Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{{
+			Name:      httpAddonName,
+			Version:   "0.15.0",
+			Inventory: buildInventory([]unstructured.Unstructured{crd}),
+		}}},
+	}}
+
+	require.NoError(t, deleteAddonObjs(context.Background(), r, s, withoutCRDFilter))
+	require.True(t, canGetFakeResource(c, crd))
+}
+
+func fixAddonDeployment(name, component string, args ...interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "kyma-system"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{addonComponentLabel: component},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": component, "args": args},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestOverrideComponents(t *testing.T) {
+	debug := v1alpha1.LogLevel("debug")
+	objs := []unstructured.Unstructured{
+		fixAddonDeployment("keda-add-ons-http-interceptor", "interceptor"),
+		fixAddonDeployment("keda-add-ons-http-external-scaler", "external-scaler", "--zap-log-level=info", "--zap-encoder=console"),
+		fixAddonDeployment("keda-add-ons-http-operator", "operator", "--leader-elect"),
+	}
+
+	overrideComponents(objs, v1alpha1.AddonCfg{
+		Interceptor: &v1alpha1.AddonComponentCfg{
+			Replicas: ptr.To[int32](3),
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		},
+		Scaler: &v1alpha1.AddonComponentCfg{LogLevel: &debug},
+	})
+
+	replicas, _, _ := unstructured.NestedInt64(objs[0].Object, "spec", "replicas")
+	require.Equal(t, int64(3), replicas)
+	containers, _, _ := unstructured.NestedSlice(objs[0].Object, "spec", "template", "spec", "containers")
+	memory, _, _ := unstructured.NestedString(containers[0].(map[string]interface{}), "resources", "limits", "memory")
+	require.Equal(t, "1Gi", memory)
+
+	containers, _, _ = unstructured.NestedSlice(objs[1].Object, "spec", "template", "spec", "containers")
+	args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+	require.Equal(t, []string{"--zap-log-level=debug", "--zap-encoder=console"}, args)
+
+	// not configured components are applied as-is
+	require.Equal(t, fixAddonDeployment("keda-add-ons-http-operator", "operator", "--leader-elect"), objs[2])
+}
+
+func TestPatchDeploymentContainer0Arg(t *testing.T) {
+	level := v1alpha1.LogLevel("error")
+	obj := fixAddonDeployment("keda-add-ons-http-interceptor", "interceptor", "--admin-port=9090")
+
+	patchDeploymentContainer0Arg(&obj, &level)
+
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	args, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "args")
+	require.Equal(t, []string{"--admin-port=9090", "--zap-log-level=error"}, args)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	"github.com/kyma-project/keda-manager/pkg/annotation"
 	v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,6 +29,13 @@ var (
 )
 
 func sFnApply(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	if s.instance.Spec.IsPlanMode() {
+		return switchState(sFnPlan)
+	}
+	// remove possible previous plan
+	s.instance.Status.Plan = nil
+	s.instance.RemoveCondition(v1alpha1.ConditionTypePlanned)
+
 	var isError bool
 	var err error
 	for _, obj := range r.Objs {
@@ -37,14 +45,10 @@ func sFnApply(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Resul
 			With("ns", obj.GetNamespace()).
 			Debug("applying")
 
-		obj = annotation.AddDoNotEditDisclaimer(obj)
-		obj.SetLabels(setCommonLabels(obj.GetLabels()))
-		if obj.Object["kind"] == "Deployment" {
-			obj.Object, err = updateImagesInDeployments(obj.Object)
-			if err != nil {
-				r.log.With("err", err).Error("update images error")
-				isError = true
-			}
+		obj, err = prepareObj(obj)
+		if err != nil {
+			r.log.With("err", err).Error("update images error")
+			isError = true
 		}
 
 		err = r.Patch(ctx, &obj, client.Apply, &client.PatchOptions{
@@ -61,7 +65,7 @@ func sFnApply(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Resul
 	}
 	// no errors
 	if !isError {
-		return switchState(sFnVerify)
+		return switchState(sFnPrune)
 	}
 
 	s.instance.UpdateStateFromErr(
@@ -72,6 +76,37 @@ func sFnApply(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Resul
 	return stopWithNoRequeue()
 }
 
+// sFnPrune deletes the objects applied in the previous reconciliation that are no longer part of the manifest
+func sFnPrune(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	if err := pruneObjs(ctx, r, s.instance.Status.Inventory); err != nil {
+		r.log.With("err", err).Error("prune error")
+		s.instance.UpdateStateFromErr(
+			v1alpha1.ConditionTypeInstalled,
+			v1alpha1.ConditionReasonPruneErr,
+			err,
+		)
+		return stopWithNoRequeue()
+	}
+
+	s.instance.Status.Inventory = buildInventory(r.Objs)
+	return switchState(sFnVerify)
+}
+
+// prepareObj sets the metadata and images managed by keda-manager on the object before it is applied
+func prepareObj(obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+	var err error
+	obj = annotation.AddDoNotEditDisclaimer(obj)
+	obj.SetLabels(setCommonLabels(obj.GetLabels()))
+	if obj.Object["kind"] == "Deployment" {
+		var updated map[string]interface{}
+		updated, err = updateImagesInDeployments(obj.Object)
+		if err == nil {
+			obj.Object = updated
+		}
+	}
+	return obj, err
+}
+
 func updateImagesInDeployments(obj map[string]interface{}) (map[string]interface{}, error) {
 	if obj["kind"] == "Deployment" {
 		var dep v1.Deployment
@@ -0,0 +1,259 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	backupLabel         = "operator.kyma-project.io/keda-backup"
+	backupCRDAnnotation = "operator.kyma-project.io/keda-backup-crd"
+	// backupChunksAnnotation holds the number of secrets of the CRD backup, the backup is complete when all of them exist
+	backupChunksAnnotation = "operator.kyma-project.io/keda-backup-chunks"
+	backupDataKey          = "objects.json"
+	backupSecretPrefix     = "keda-backup-"
+	maxBackupChunkLength   = 900 * 1024 // keep the secrets below the 1MiB limit
+
+	restoreRetryInterval = 30 * time.Second
+)
+
+// sFnRestoreCustomResources re-creates the custom resources stored during the cascade deletion.
+// KEDA is already verified at this point, so the resources that can't be restored don't fail the installation:
+// the ones rejected by the api server, e.g. because their namespace is gone, are skipped and reported in
+// the Restored condition, the restore of the others is retried until the backup can be removed
+func sFnRestoreCustomResources(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	result, err := restoreCustomResources(ctx, r, s.instance.GetNamespace())
+	if result.restored > 0 {
+		r.log.Infof("restored %d custom resources from backup", result.restored)
+	}
+
+	switch {
+	case err != nil:
+		r.log.With("err", err).Warn("failed to restore custom resources from backup, retrying")
+		s.instance.Status.State = v1alpha1.StateWarning
+		s.instance.UpdateCondition(v1alpha1.ConditionTypeRestored, metav1.ConditionFalse, v1alpha1.ConditionReasonRestoreErr,
+			fmt.Sprintf("restore of custom resources from backup failed, retrying: %s", err))
+		// the add-ons are reconciled anyway, the restore is retried with the next reconciliation
+		s.addonsResult = &ctrl.Result{RequeueAfter: restoreRetryInterval}
+	case len(result.skipped) > 0:
+		s.instance.Status.State = v1alpha1.StateWarning
+		s.instance.UpdateCondition(v1alpha1.ConditionTypeRestored, metav1.ConditionFalse, v1alpha1.ConditionReasonRestoreErr,
+			fmt.Sprintf("%d custom resources restored from backup, %d skipped: %s",
+				result.restored, len(result.skipped), strings.Join(result.skipped, "; ")))
+	case result.found:
+		s.instance.UpdateCondition(v1alpha1.ConditionTypeRestored, metav1.ConditionTrue, v1alpha1.ConditionReasonRestored,
+			fmt.Sprintf("%d custom resources restored from backup", result.restored))
+	}
+	return switchState(sFnHandleAddons)
+}
+
+func backupCustomResources(ctx context.Context, r *fsm, namespace string) error {
+	existing, err := listBackupSecrets(ctx, r, namespace)
+	if err != nil {
+		return err
+	}
+	backups := map[string][]corev1.Secret{}
+	for _, secret := range existing {
+		crdName := secret.GetAnnotations()[backupCRDAnnotation]
+		backups[crdName] = append(backups[crdName], secret)
+	}
+
+	for _, obj := range r.Objs {
+		if !isCRD(obj) {
+			continue
+		}
+		// keep the backup of the first deletion attempt, the instances might be already removed in the next one
+		if isBackupComplete(backups[obj.GetName()]) {
+			continue
+		}
+		// the previous attempt failed before all chunks were stored, the instances are not removed yet so back them up again
+		for i := range backups[obj.GetName()] {
+			if err := r.Delete(ctx, &backups[obj.GetName()][i]); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+
+		list, err := buildResourceListFromCRD(obj)
+		if err != nil {
+			return err
+		}
+		err = r.List(ctx, &list)
+		if client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+
+		secrets, err := buildBackupSecrets(obj.GetName(), namespace, list.Items)
+		if err != nil {
+			return err
+		}
+		for i := range secrets {
+			if err := r.Create(ctx, &secrets[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isBackupComplete returns true if all chunks of the CRD backup exist
+func isBackupComplete(secrets []corev1.Secret) bool {
+	if len(secrets) == 0 {
+		return false
+	}
+	for _, secret := range secrets {
+		chunks, err := strconv.Atoi(secret.GetAnnotations()[backupChunksAnnotation])
+		if err != nil || chunks != len(secrets) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildBackupSecrets splits the instances of the given CRD into secrets below the size limit
+func buildBackupSecrets(crdName, namespace string, items []unstructured.Unstructured) ([]corev1.Secret, error) {
+	var secrets []corev1.Secret
+	var chunk []json.RawMessage
+	var chunkLength int
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("%s%s-%d", backupSecretPrefix, crdName, len(secrets)),
+				Namespace:   namespace,
+				Labels:      map[string]string{backupLabel: "true"},
+				Annotations: map[string]string{backupCRDAnnotation: crdName},
+			},
+			Data: map[string][]byte{backupDataKey: data},
+		})
+		chunk, chunkLength = nil, 0
+		return nil
+	}
+
+	for _, item := range items {
+		data, err := json.Marshal(cleanupBackupObj(item).Object)
+		if err != nil {
+			return nil, err
+		}
+		if chunkLength+len(data) > maxBackupChunkLength {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		chunk = append(chunk, data)
+		chunkLength += len(data)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		secrets[i].Annotations[backupChunksAnnotation] = strconv.Itoa(len(secrets))
+	}
+	return secrets, nil
+}
+
+// cleanupBackupObj drops the fields set by the api server so that the object can be created again
+func cleanupBackupObj(obj unstructured.Unstructured) unstructured.Unstructured {
+	result := obj.DeepCopy()
+	unstructured.RemoveNestedField(result.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "finalizers"} {
+		unstructured.RemoveNestedField(result.Object, "metadata", field)
+	}
+	return *result
+}
+
+type restoreResult struct {
+	// found is set when a backup exists
+	found    bool
+	restored int
+	// skipped lists the resources rejected by the api server, they are not restored
+	skipped []string
+}
+
+// restoreCustomResources re-creates the resources stored in the backup and removes every backup secret
+// whose resources are either restored or skipped; the returned error lists the resources to retry
+func restoreCustomResources(ctx context.Context, r *fsm, namespace string) (restoreResult, error) {
+	var result restoreResult
+	secrets, err := listBackupSecrets(ctx, r, namespace)
+	if err != nil || len(secrets) == 0 {
+		return result, err
+	}
+	result.found = true
+
+	// authentications are referenced by scaled objects and jobs, restore them first
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return strings.Contains(secrets[i].GetName(), "triggerauthentications") &&
+			!strings.Contains(secrets[j].GetName(), "triggerauthentications")
+	})
+
+	var restoreErr error
+	for _, secret := range secrets {
+		var items []map[string]interface{}
+		if err := json.Unmarshal(secret.Data[backupDataKey], &items); err != nil {
+			result.skipped = append(result.skipped, fmt.Sprintf("invalid backup %s: %s", secret.GetName(), err))
+			items = nil
+		}
+
+		var secretErr error
+		for _, item := range items {
+			obj := unstructured.Unstructured{Object: item}
+			err := r.Create(ctx, &obj)
+			switch {
+			case err == nil:
+				result.restored++
+			case apierrors.IsAlreadyExists(err):
+			case isPermanentRestoreErr(err):
+				result.skipped = append(result.skipped, fmt.Sprintf("%s %s/%s: %s",
+					obj.GetKind(), obj.GetNamespace(), obj.GetName(), err))
+			default:
+				secretErr = errors.Join(secretErr, err)
+			}
+		}
+
+		if secretErr != nil {
+			restoreErr = errors.Join(restoreErr, secretErr)
+			continue
+		}
+		if err := r.Delete(ctx, &secret); client.IgnoreNotFound(err) != nil {
+			restoreErr = errors.Join(restoreErr, err)
+		}
+	}
+	return result, restoreErr
+}
+
+// isPermanentRestoreErr returns true if the resource is rejected by the api server and
+// creating it again can't succeed, e.g. because its namespace doesn't exist anymore
+func isPermanentRestoreErr(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsInvalid(err) || apierrors.IsBadRequest(err)
+}
+
+func listBackupSecrets(ctx context.Context, r *fsm, namespace string) ([]corev1.Secret, error) {
+	var secrets corev1.SecretList
+	err := r.List(ctx, &secrets,
+		client.InNamespace(namespace),
+		client.MatchingLabels{backupLabel: "true"},
+	)
+	return secrets.Items, err
+}
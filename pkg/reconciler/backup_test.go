@@ -0,0 +1,218 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func fixBackupTestCR(name string) unstructured.Unstructured {
+	cr := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "TestResource",
+			"apiVersion": "testgroup.io/v1",
+			"metadata": map[string]interface{}{
+				"name":            name,
+				"namespace":       "test",
+				"resourceVersion": "12",
+				"uid":             "a-b-c",
+			},
+			"spec": map[string]interface{}{
+				"value": "test",
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+	return cr
+}
+
+func Test_buildBackupSecrets(t *testing.T) {
+	t.Run("skip empty backup", func(t *testing.T) {
+		secrets, err := buildBackupSecrets("test-crd", "kyma-system", nil)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	t.Run("remove server fields", func(t *testing.T) {
+		secrets, err := buildBackupSecrets("test-crd", "kyma-system", []unstructured.Unstructured{fixBackupTestCR("test")})
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		require.Equal(t, "kyma-system", secrets[0].GetNamespace())
+		require.Equal(t, "true", secrets[0].GetLabels()[backupLabel])
+
+		data := string(secrets[0].Data[backupDataKey])
+		require.Contains(t, data, `"value":"test"`)
+		require.NotContains(t, data, "resourceVersion")
+		require.NotContains(t, data, "uid")
+		require.NotContains(t, data, "status")
+	})
+
+	t.Run("split large backup", func(t *testing.T) {
+		var items []unstructured.Unstructured
+		for i := 0; i < 3; i++ {
+			cr := fixBackupTestCR(fmt.Sprintf("test-%d", i))
+			cr.Object["spec"] = map[string]interface{}{"value": strings.Repeat("x", maxBackupChunkLength/2)}
+			items = append(items, cr)
+		}
+
+		secrets, err := buildBackupSecrets("test-crd", "kyma-system", items)
+		require.NoError(t, err)
+		require.Len(t, secrets, 3)
+		require.Equal(t, "keda-backup-test-crd-2", secrets[2].GetName())
+	})
+}
+
+func Test_backupAndRestoreCustomResources(t *testing.T) {
+	ctx := context.Background()
+	cr := fixBackupTestCR("test")
+	c := fake.NewClientBuilder().WithObjects(&cr).Build()
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		K8s: K8s{Client: c},
+		Cfg: Cfg{Objs: []unstructured.Unstructured{testCRD}},
+	}
+
+	require.NoError(t, backupCustomResources(ctx, r, "kyma-system"))
+	require.NoError(t, c.Delete(ctx, &cr))
+
+	// the second backup attempt must not override the first one
+	require.NoError(t, backupCustomResources(ctx, r, "kyma-system"))
+
+	result, err := restoreCustomResources(ctx, r, "kyma-system")
+	require.NoError(t, err)
+	require.Equal(t, 1, result.restored)
+	require.True(t, canGetFakeResource(c, cr))
+
+	var secrets corev1.SecretList
+	require.NoError(t, c.List(ctx, &secrets))
+	require.Empty(t, secrets.Items)
+}
+
+func Test_backupCustomResources_partialBackup(t *testing.T) {
+	ctx := context.Background()
+	cr := fixBackupTestCR("test")
+	// the previous attempt stored the first of two chunks only
+	partial := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupSecretPrefix + "test-crd-0",
+			Namespace: "kyma-system",
+			Labels:    map[string]string{backupLabel: "true"},
+			Annotations: map[string]string{
+				backupCRDAnnotation:    "test-crd",
+				backupChunksAnnotation: "2",
+			},
+		},
+		Data: map[string][]byte{backupDataKey: []byte("[]")},
+	}
+	c := fake.NewClientBuilder().WithObjects(&cr, &partial).Build()
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		K8s: K8s{Client: c},
+		Cfg: Cfg{Objs: []unstructured.Unstructured{testCRD}},
+	}
+
+	require.NoError(t, backupCustomResources(ctx, r, "kyma-system"))
+
+	secrets, err := listBackupSecrets(ctx, r, "kyma-system")
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	require.Equal(t, "1", secrets[0].GetAnnotations()[backupChunksAnnotation])
+	require.Contains(t, string(secrets[0].Data[backupDataKey]), `"name":"test"`)
+}
+
+func Test_sFnRestoreCustomResources(t *testing.T) {
+	ctx := context.Background()
+	fixBackup := func(t *testing.T, names ...string) *corev1.Secret {
+		var items []unstructured.Unstructured
+		for _, name := range names {
+			items = append(items, fixBackupTestCR(name))
+		}
+		secrets, err := buildBackupSecrets("test-crd", "kyma-system", items)
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		return &secrets[0]
+	}
+	fixCreateErr := func(name string, createErr error) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if obj.GetName() == name {
+					return createErr
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}
+	}
+
+	t.Run("skip resources that can't be restored", func(t *testing.T) {
+		gone := apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "test")
+		c := fake.NewClientBuilder().WithObjects(fixBackup(t, "gone", "test")).
+			WithInterceptorFuncs(fixCreateErr("gone", gone)).Build()
+		r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}}
+		s := &systemState{}
+
+		next, result, err := sFnRestoreCustomResources(ctx, r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleAddons, next)
+		require.Nil(t, s.addonsResult)
+		require.Equal(t, v1alpha1.StateWarning, s.instance.Status.State)
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeRestored))
+		require.Equal(t, metav1.ConditionFalse, condition.Status)
+		require.Equal(t, string(v1alpha1.ConditionReasonRestoreErr), condition.Reason)
+		require.Contains(t, condition.Message, "1 custom resources restored from backup, 1 skipped: TestResource test/gone")
+
+		secrets, err := listBackupSecrets(ctx, r, "kyma-system")
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	t.Run("retry failed restore", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(fixBackup(t, "test")).
+			WithInterceptorFuncs(fixCreateErr("test", apierrors.NewInternalError(errors.New("etcd unavailable")))).Build()
+		r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}}
+		s := &systemState{}
+
+		next, _, err := sFnRestoreCustomResources(ctx, r, s)
+		require.NoError(t, err)
+		requireEqualFunc(t, sFnHandleAddons, next)
+		require.Equal(t, &ctrl.Result{RequeueAfter: restoreRetryInterval}, s.addonsResult)
+		require.Equal(t, v1alpha1.StateWarning, s.instance.Status.State)
+
+		secrets, err := listBackupSecrets(ctx, r, "kyma-system")
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+	})
+
+	t.Run("restore all resources", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(fixBackup(t, "test")).Build()
+		r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}}
+		s := &systemState{}
+
+		_, _, err := sFnRestoreCustomResources(ctx, r, s)
+		require.NoError(t, err)
+		require.Nil(t, s.addonsResult)
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeRestored))
+		require.Equal(t, metav1.ConditionTrue, condition.Status)
+		require.Equal(t, "1 custom resources restored from backup", condition.Message)
+	})
+}
@@ -0,0 +1,53 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DefaultSpec returns the logging and resources of the KEDA components in the KEDA manifest;
+// the reconciler keeps these values for the components that are not configured in the Keda CR
+func DefaultSpec(objs []unstructured.Unstructured) (v1alpha1.KedaSpec, error) {
+	spec := v1alpha1.KedaSpec{
+		Logging:   &v1alpha1.LoggingCfg{},
+		Resources: &v1alpha1.Resources{},
+	}
+	for _, component := range []struct {
+		isDeployment predicate
+		logging      **v1alpha1.LoggingCommonCfg
+		resources    **corev1.ResourceRequirements
+	}{
+		{isKedaOperatorDeployment, &spec.Logging.Operator, &spec.Resources.Operator},
+		{isKedaMatricsServerDeployment, &spec.Logging.MetricsServer, &spec.Resources.MetricsServer},
+		{isAdmissionWebhooksDeployment, &spec.Logging.AdmissionWebhook, &spec.Resources.AdmissionWebhook},
+	} {
+		container, err := firstContainer(objs, component.isDeployment)
+		if err != nil {
+			return v1alpha1.KedaSpec{}, err
+		}
+		*component.logging = v1alpha1.LoggingCommonCfgFromArgs(container.Args)
+		*component.resources = container.Resources.DeepCopy()
+	}
+	return spec, nil
+}
+
+func firstContainer(objs []unstructured.Unstructured, isDeployment predicate) (*corev1.Container, error) {
+	for _, obj := range objs {
+		if !isDeployment(obj) {
+			continue
+		}
+		var deployment appsv1.Deployment
+		if err := fromUnstructured(obj.Object, &deployment); err != nil {
+			return nil, err
+		}
+		if len(deployment.Spec.Template.Spec.Containers) == 0 {
+			return nil, fmt.Errorf("deployment %s has no containers", deployment.GetName())
+		}
+		return &deployment.Spec.Template.Spec.Containers[0], nil
+	}
+	return nil, fmt.Errorf("%w: KEDA deployment", ErrNotFound)
+}
@@ -0,0 +1,40 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/resources"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDefaultSpec(t *testing.T) {
+	objs, err := resources.LoadFromPaths("../../keda.yaml")
+	require.NoError(t, err)
+
+	t.Run("read the defaults from the KEDA manifest", func(t *testing.T) {
+		spec, err := DefaultSpec(objs)
+		require.NoError(t, err)
+
+		require.Equal(t, v1alpha1.CommonLogLevelInfo, *spec.Logging.Operator.Level)
+		require.Equal(t, v1alpha1.LogFormatJSON, *spec.Logging.MetricsServer.Format)
+		require.Equal(t, v1alpha1.TimeEncodingRFC3339, *spec.Logging.AdmissionWebhook.TimeEncoding)
+		require.True(t, spec.Resources.Operator.Limits.Memory().Equal(resource.MustParse("1000Mi")))
+		require.True(t, spec.Resources.MetricsServer.Limits.Cpu().Equal(resource.MustParse("1")))
+		require.True(t, spec.Resources.AdmissionWebhook.Requests.Cpu().Equal(resource.MustParse("100m")))
+	})
+
+	t.Run("missing deployment", func(t *testing.T) {
+		var withoutWebhooks []unstructured.Unstructured
+		for _, obj := range objs {
+			if !isAdmissionWebhooksDeployment(obj) {
+				withoutWebhooks = append(withoutWebhooks, obj)
+			}
+		}
+
+		_, err := DefaultSpec(withoutWebhooks)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
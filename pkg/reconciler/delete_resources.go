@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -19,6 +20,8 @@ const (
 	defaultDeletionStrategy = safeDeletionStrategy
 	kedaOperatorLeaseName   = "operator.keda.sh"
 	kedaManagerLeaseName    = "4123c01c.operator.kyma-project.io"
+	// maxReportedOrphans caps the number of blocking resources reported in the status and in the condition message
+	maxReportedOrphans = 20
 )
 
 func fixLeaseObject(leaseName string) unstructured.Unstructured {
@@ -42,8 +45,20 @@ func sFnDeleteResources(_ context.Context, _ *fsm, s *systemState) (stateFn, *ct
 		return stopWithRequeue()
 	}
 
-	// TODO: thinkg about deletion configuration
-	return switchState(deletionStrategyBuilder(defaultDeletionStrategy))
+	return switchState(deletionStrategyBuilder(deletionStrategyFromPolicy(s.instance.Spec.DeletionPolicy)))
+}
+
+func deletionStrategyFromPolicy(policy v1alpha1.DeletionPolicy) deletionStrategy {
+	switch policy {
+	case v1alpha1.DeletionPolicyCascade:
+		return cascadeDeletionStrategy
+	case v1alpha1.DeletionPolicyKeepCRDs:
+		return upstreamDeletionStrategy
+	case v1alpha1.DeletionPolicySafe:
+		return safeDeletionStrategy
+	default:
+		return defaultDeletionStrategy
+	}
 }
 
 type deletionStrategy string
@@ -68,6 +83,11 @@ func deletionStrategyBuilder(strategy deletionStrategy) stateFn {
 }
 
 func sFnCascadeDeletionState(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	// custom resources are removed together with the CRDs, keep them to restore on the next installation
+	if err := backupCustomResources(ctx, r, s.instance.GetNamespace()); err != nil {
+		s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonBackupErr, err)
+		return stopWithErrorAndNoRequeue(err)
+	}
 	return deleteResourcesWithFilter(ctx, r, s)
 }
 
@@ -76,38 +96,127 @@ func sFnUpstreamDeletionState(ctx context.Context, r *fsm, s *systemState) (stat
 }
 
 func sFnSafeDeletionState(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	if err := checkAddonOrphanResources(ctx, r); err != nil {
-		s.instance.UpdateStateFromWarning(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonAddonInUse, err)
+	orphans, err := findOrphanResources(ctx, r, s)
+	if err != nil {
+		s.instance.UpdateStateFromWarning(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonDeletionErr, err)
 		return stopWithErrorAndNoRequeue(err)
 	}
 
-	if err := checkCRDOrphanResources(ctx, r); err != nil {
-		s.instance.UpdateStateFromWarning(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonDeletionErr, err)
+	if len(orphans) > 0 {
+		s.instance.Status.Orphans = buildOrphanReport(orphans)
+		err := orphanResourcesError(orphans)
+		s.instance.UpdateStateFromWarning(v1alpha1.ConditionTypeDeleted, orphanResourcesReason(orphans), err)
 		return stopWithErrorAndNoRequeue(err)
 	}
 
+	s.instance.Status.Orphans = nil
 	return deleteResourcesWithFilter(ctx, r, s)
 }
 
-// checkAddonOrphanResources refuses to tear down the HTTP add-on while there
-// are HTTPScaledObjects on the cluster. The add-on owns the HTTPScaledObject
-// CRD, so removing the add-on before the user removes their HTTPScaledObjects
-// would leave orphaned resources of an unknown type. The check is a no-op when
-// the add-on was never installed (r.AddonObjs is empty).
-func checkAddonOrphanResources(ctx context.Context, r *fsm) error {
-	if len(r.AddonObjs) == 0 {
-		return nil
+// findOrphanResources returns all custom resources that block the safe deletion: instances of
+// every CRD in the manifest and HTTPScaledObjects when the HTTP add-on is installed
+func findOrphanResources(ctx context.Context, r *fsm, s *systemState) ([]v1alpha1.OrphanedResource, error) {
+	addonOrphans, err := addonOrphanResources(ctx, r, s)
+	if err != nil {
+		return nil, err
 	}
-	count, err := httpScaledObjectsInUse(ctx, r.Client)
+
+	crdOrphans, err := crdOrphanResources(ctx, r)
 	if err != nil {
-		return fmt.Errorf("cannot verify HTTPScaledObject usage: %w", err)
+		return nil, err
 	}
-	if count > 0 {
-		return fmt.Errorf(
-			"%d HTTPScaledObject(s) still exist on the cluster; delete them before uninstalling the Keda module",
-			count)
+
+	return append(addonOrphans, crdOrphans...), nil
+}
+
+// addonOrphanResources returns the resources depending on the installed add-ons, e.g. the HTTPScaledObjects
+// of the HTTP add-on. The add-ons own the CRDs of these resources, so removing an add-on before the user removes
+// them would leave orphaned resources of an unknown type. Add-ons that were never installed are skipped.
+func addonOrphanResources(ctx context.Context, r *fsm, s *systemState) ([]v1alpha1.OrphanedResource, error) {
+	var orphans []v1alpha1.OrphanedResource
+	for _, a := range r.Addons {
+		if len(installedAddonObjs(r, s, a)) == 0 {
+			continue
+		}
+		inUse, err := a.InUse(ctx, r.Client)
+		if err != nil {
+			return nil, fmt.Errorf("cannot verify usage of the %s: %w", a.Title(), err)
+		}
+		orphans = append(orphans, toOrphanResources(inUse)...)
 	}
-	return nil
+	return orphans, nil
+}
+
+func crdOrphanResources(ctx context.Context, r *fsm) ([]v1alpha1.OrphanedResource, error) {
+	var orphans []v1alpha1.OrphanedResource
+	for _, obj := range r.Objs {
+		if !isCRD(obj) {
+			continue
+		}
+
+		crdList, err := buildResourceListFromCRD(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		err = r.List(ctx, &crdList)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+
+		orphans = append(orphans, toOrphanResources(crdList.Items)...)
+	}
+
+	return orphans, nil
+}
+
+func toOrphanResources(items []unstructured.Unstructured) []v1alpha1.OrphanedResource {
+	var orphans []v1alpha1.OrphanedResource
+	for _, item := range items {
+		orphans = append(orphans, v1alpha1.OrphanedResource{
+			APIVersion: item.GetAPIVersion(),
+			Kind:       item.GetKind(),
+			Namespace:  item.GetNamespace(),
+			Name:       item.GetName(),
+		})
+	}
+	return orphans
+}
+
+func buildOrphanReport(orphans []v1alpha1.OrphanedResource) *v1alpha1.OrphanReport {
+	report := v1alpha1.OrphanReport{
+		Total:     len(orphans),
+		Resources: orphans,
+	}
+	if len(orphans) > maxReportedOrphans {
+		report.Resources = orphans[:maxReportedOrphans]
+	}
+	return &report
+}
+
+func orphanResourcesError(orphans []v1alpha1.OrphanedResource) error {
+	names := make([]string, 0, maxReportedOrphans)
+	for i, orphan := range orphans {
+		if i == maxReportedOrphans {
+			names = append(names, fmt.Sprintf("and %d more", len(orphans)-maxReportedOrphans))
+			break
+		}
+		names = append(names, fmt.Sprintf("%s %s", orphan.Kind, client.ObjectKey{
+			Namespace: orphan.Namespace,
+			Name:      orphan.Name,
+		}))
+	}
+	return fmt.Errorf("%d resource(s) still exist on the cluster; delete them before uninstalling the Keda module: %s",
+		len(orphans), strings.Join(names, ", "))
+}
+
+func orphanResourcesReason(orphans []v1alpha1.OrphanedResource) v1alpha1.ConditionReason {
+	for _, orphan := range orphans {
+		if orphan.Kind == httpScaledObjectKind {
+			return v1alpha1.ConditionReasonAddonInUse
+		}
+	}
+	return v1alpha1.ConditionReasonDeletionErr
 }
 
 func withoutCRDFilter(u unstructured.Unstructured) bool {
@@ -119,14 +228,30 @@ type filterFunc func(unstructured.Unstructured) bool
 func deleteResourcesWithFilter(ctx context.Context, r *fsm, s *systemState, filterFunc ...filterFunc) (stateFn, *ctrl.Result, error) {
 	//ensure lease object will be removed as well
 	r.AddLeaseObjs()
+	// managed PodDisruptionBudgets are not part of the manifest
+	r.AddPodDisruptionBudgetObjs()
+	// external scalers are not part of the manifest
+	r.AddExternalScalerObjs(s.instance.Spec.ExternalScalers)
+	// keda-operator Roles in the watched namespaces replace the ClusterRole from the manifest
+	r.AddWatchNamespaceObjs(s.instance.Spec.WatchNamespaces)
+
+	// managed PriorityClass is not part of the manifest, it is kept while keda-manager uses it
+	priorityClassInUse, err := isPriorityClassUsedOutsideModule(ctx, r)
+	if err != nil {
+		s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonDeletionErr, err)
+		return stopWithErrorAndNoRequeue(err)
+	}
+	if !priorityClassInUse {
+		r.AddPriorityClassObj()
+	}
 
 	// Also remove any HTTP add-on resources.
-	if err := deleteAddonObjs(ctx, r); err != nil {
+	if err := deleteAddonObjs(ctx, r, s, filterFunc...); err != nil {
 		s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonDeletionErr, err)
 		return stopWithErrorAndNoRequeue(err)
 	}
 
-	err := deleteResources(ctx, r, r.Objs, filterFunc)
+	err = deleteResources(ctx, r, r.Objs, filterFunc)
 	if err != nil {
 		s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeDeleted, v1alpha1.ConditionReasonDeletionErr, err)
 		return stopWithErrorAndNoRequeue(err)
@@ -178,30 +303,6 @@ func fitToFilters(u unstructured.Unstructured, filterFunc ...filterFunc) bool {
 	return true
 }
 
-func checkCRDOrphanResources(ctx context.Context, r *fsm) error {
-	for _, obj := range r.Objs {
-		if !isCRD(obj) {
-			continue
-		}
-
-		crdList, err := buildResourceListFromCRD(obj)
-		if err != nil {
-			return err
-		}
-
-		err = r.List(ctx, &crdList)
-		if client.IgnoreNotFound(err) != nil {
-			return err
-		}
-
-		if len(crdList.Items) > 0 {
-			return fmt.Errorf("found %d items with VersionKind %s", len(crdList.Items), crdList.GetAPIVersion())
-		}
-	}
-
-	return nil
-}
-
 func isCRD(u unstructured.Unstructured) bool {
 	return u.GroupVersionKind().GroupKind() == apiextensionsv1.Kind("CustomResourceDefinition")
 }
@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -11,6 +12,8 @@ import (
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -139,16 +142,41 @@ func Test_sFnDeleteResources(t *testing.T) {
 			stateFn,
 		)
 	})
+
+	t.Run("choose deletion strategy from deletion policy", func(t *testing.T) {
+		for policy, expected := range map[v1alpha1.DeletionPolicy]stateFn{
+			v1alpha1.DeletionPolicySafe:     sFnSafeDeletionState,
+			v1alpha1.DeletionPolicyCascade:  sFnCascadeDeletionState,
+			v1alpha1.DeletionPolicyKeepCRDs: sFnUpstreamDeletionState,
+		} {
+			system := systemState{
+				instance: v1alpha1.Keda{
+					Spec: v1alpha1.KedaSpec{DeletionPolicy: policy},
+					Status: v1alpha1.Status{
+						Conditions: []metav1.Condition{
+							{
+								Type: string(v1alpha1.ConditionTypeDeleted),
+							},
+						},
+					},
+				},
+			}
+
+			stateFn, _, err := sFnDeleteResources(context.Background(), &fsm{}, &system)
+			require.NoError(t, err)
+			requireEqualFunc(t, expected, stateFn)
+		}
+	})
 }
 
 func Test_sFnDeleteStrategy(t *testing.T) {
 	t.Run("cascade delete strategy", func(t *testing.T) {
 		clientBuilder := fake.NewClientBuilder().
-			WithObjects(&testDeployment, &testEmptyCRD, &testService)
+			WithObjects(&testDeployment, &testCRD, &testService, &testCR)
 		client := clientBuilder.Build()
 		ctx := context.Background()
 		objs := []unstructured.Unstructured{
-			testDeployment, testEmptyCRD, testService,
+			testDeployment, testCRD, testService,
 		}
 		r := &fsm{
 			log: zap.NewNop().Sugar(),
@@ -189,8 +217,14 @@ func Test_sFnDeleteStrategy(t *testing.T) {
 
 		// check deletion progress
 		require.False(t, canGetFakeResource(client, testDeployment))
-		require.False(t, canGetFakeResource(client, testEmptyCRD))
+		require.False(t, canGetFakeResource(client, testCRD))
 		require.False(t, canGetFakeResource(client, testService))
+
+		// check custom resources backup
+		var secrets corev1.SecretList
+		require.NoError(t, client.List(ctx, &secrets))
+		require.Len(t, secrets.Items, 1)
+		require.Equal(t, "keda-backup-test-crd-0", secrets.Items[0].GetName())
 	})
 
 	t.Run("upstream delete strategy", func(t *testing.T) {
@@ -332,7 +366,16 @@ func Test_sFnDeleteStrategy(t *testing.T) {
 		conditionDeleted := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeDeleted))
 		require.NotNil(t, conditionDeleted)
 		require.Equal(t, string(v1alpha1.ConditionReasonDeletionErr), conditionDeleted.Reason)
-		require.Equal(t, "found 1 items with VersionKind testgroup.io/v1", conditionDeleted.Message)
+		require.Equal(t,
+			"1 resource(s) still exist on the cluster; delete them before uninstalling the Keda module: TestResource test/test-crd",
+			conditionDeleted.Message,
+		)
+		require.Equal(t, &v1alpha1.OrphanReport{
+			Total: 1,
+			Resources: []v1alpha1.OrphanedResource{
+				{APIVersion: "testgroup.io/v1", Kind: "TestResource", Namespace: "test", Name: "test-crd"},
+			},
+		}, s.instance.Status.Orphans)
 
 		// check deletion progress
 		require.True(t, canGetFakeResource(client, testDeployment))
@@ -405,6 +448,59 @@ func Test_deleteResourcesWithFilter(t *testing.T) {
 	})
 }
 
+func Test_deleteResourcesWithFilter_priorityClass(t *testing.T) {
+	fixDeployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kyma-system"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{PriorityClassName: v1alpha1.DefaultPriorityClassName},
+				},
+			},
+		}
+	}
+	operatorDeployment := unstructured.Unstructured{}
+	operatorDeployment.SetAPIVersion("apps/v1")
+	operatorDeployment.SetKind("Deployment")
+	operatorDeployment.SetNamespace("kyma-system")
+	operatorDeployment.SetName(operatorName)
+
+	t.Run("keep priority class used by keda-manager", func(t *testing.T) {
+		priorityClass := fixPriorityClassObject()
+		c := fake.NewClientBuilder().
+			WithObjects(&priorityClass, fixDeployment("keda-manager"), fixDeployment(operatorName)).
+			Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{operatorDeployment}},
+		}
+
+		_, _, err := deleteResourcesWithFilter(context.Background(), r, &systemState{})
+		require.NoError(t, err)
+
+		require.True(t, canGetFakeResource(c, fixPriorityClassObject()))
+		require.False(t, canGetFakeResource(c, operatorDeployment))
+	})
+
+	t.Run("delete priority class used only by the module", func(t *testing.T) {
+		priorityClass := fixPriorityClassObject()
+		c := fake.NewClientBuilder().
+			WithObjects(&priorityClass, fixDeployment(operatorName)).
+			Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{operatorDeployment}},
+		}
+
+		_, _, err := deleteResourcesWithFilter(context.Background(), r, &systemState{})
+		require.NoError(t, err)
+
+		require.False(t, canGetFakeResource(c, fixPriorityClassObject()))
+	})
+}
+
 func canGetFakeResource(c client.Client, u unstructured.Unstructured) bool {
 	err := c.Get(context.Background(),
 		types.NamespacedName{
@@ -414,26 +510,33 @@ func canGetFakeResource(c client.Client, u unstructured.Unstructured) bool {
 	return err == nil
 }
 
-func TestCheckAddonOrphanResources(t *testing.T) {
+func TestAddonOrphanResources(t *testing.T) {
 	t.Run("no add-on installed → no-op", func(t *testing.T) {
-		c := fake.NewClientBuilder().Build()
+		c := fake.NewClientBuilder().WithObjects(newHTTPScaledObject("demo-app", "http-echo")).Build()
 		r := &fsm{
 			log: zap.NewNop().Sugar(),
 			K8s: K8s{Client: c},
-			Cfg: Cfg{},
+			Cfg: Cfg{Addons: DefaultAddons()},
 		}
-		require.NoError(t, checkAddonOrphanResources(context.Background(), r))
+		orphans, err := addonOrphanResources(context.Background(), r, &systemState{})
+		require.NoError(t, err)
+		require.Empty(t, orphans)
 	})
 	t.Run("add-on installed, no HTTPScaledObjects → pass", func(t *testing.T) {
 		c := fake.NewClientBuilder().Build()
 		r := &fsm{
 			log: zap.NewNop().Sugar(),
 			K8s: K8s{Client: c},
-			Cfg: Cfg{AddonObjs: []unstructured.Unstructured{{}}},
+			Cfg: Cfg{
+				Addons:    DefaultAddons(),
+				AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {{}}},
+			},
 		}
-		require.NoError(t, checkAddonOrphanResources(context.Background(), r))
+		orphans, err := addonOrphanResources(context.Background(), r, &systemState{})
+		require.NoError(t, err)
+		require.Empty(t, orphans)
 	})
-	t.Run("add-on installed, HTTPScaledObjects exist → error", func(t *testing.T) {
+	t.Run("add-on installed, HTTPScaledObjects exist → reported", func(t *testing.T) {
 		c := fake.NewClientBuilder().WithObjects(
 			newHTTPScaledObject("demo-app", "http-echo"),
 			newHTTPScaledObject("billing", "api"),
@@ -441,11 +544,77 @@ func TestCheckAddonOrphanResources(t *testing.T) {
 		r := &fsm{
 			log: zap.NewNop().Sugar(),
 			K8s: K8s{Client: c},
-			Cfg: Cfg{AddonObjs: []unstructured.Unstructured{{}}},
+			Cfg: Cfg{
+				Addons:    DefaultAddons(),
+				AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {{}}},
+			},
 		}
-		err := checkAddonOrphanResources(context.Background(), r)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "2 HTTPScaledObject(s)")
-		require.Contains(t, err.Error(), "uninstalling the Keda module")
+		orphans, err := addonOrphanResources(context.Background(), r, &systemState{})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []v1alpha1.OrphanedResource{
+			{APIVersion: "http.keda.sh/v1alpha1", Kind: httpScaledObjectKind, Namespace: "demo-app", Name: "http-echo"},
+			{APIVersion: "http.keda.sh/v1alpha1", Kind: httpScaledObjectKind, Namespace: "billing", Name: "api"},
+		}, orphans)
 	})
+	t.Run("add-on installed in previous reconciliation → reported", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(newHTTPScaledObject("demo-app", "http-echo")).Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Addons: DefaultAddons()},
+		}
+		s := &systemState{instance: v1alpha1.Keda{Status: v1alpha1.Status{
+			Addons: []v1alpha1.AddonStatus{{
+				Name:    httpAddonName,
+				Version: "0.15.0",
+				Inventory: []v1alpha1.InventoryEntry{
+					{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kyma-system", Name: "keda-add-ons-http-interceptor"},
+				},
+			}},
+		}}}
+		orphans, err := addonOrphanResources(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Len(t, orphans, 1)
+	})
+}
+
+func Test_sFnSafeDeletionState_orphans(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		newHTTPScaledObject("demo-app", "http-echo"),
+		newHTTPScaledObject("billing", "api"),
+	).Build()
+	r := &fsm{
+		log: zap.NewNop().Sugar(),
+		K8s: K8s{Client: c},
+		Cfg: Cfg{
+			Addons:    DefaultAddons(),
+			AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {{}}},
+		},
+	}
+	s := &systemState{}
+
+	_, _, err := sFnSafeDeletionState(context.Background(), r, s)
+	require.NoError(t, err)
+	require.NotNil(t, s.instance.Status.Orphans)
+	require.Equal(t, 2, s.instance.Status.Orphans.Total)
+	require.Len(t, s.instance.Status.Orphans.Resources, 2)
+
+	condition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeDeleted))
+	require.NotNil(t, condition)
+	require.Equal(t, v1alpha1.ConditionReasonAddonInUse, condition.Reason)
+	require.Contains(t, condition.Message, "2 resource(s) still exist")
+	require.Contains(t, condition.Message, "HTTPScaledObject demo-app/http-echo")
+	require.Contains(t, condition.Message, "HTTPScaledObject billing/api")
+}
+
+func Test_buildOrphanReport(t *testing.T) {
+	orphans := make([]v1alpha1.OrphanedResource, maxReportedOrphans+5)
+	for i := range orphans {
+		orphans[i] = v1alpha1.OrphanedResource{Kind: "ScaledObject", Namespace: "default", Name: fmt.Sprintf("so-%d", i)}
+	}
+
+	report := buildOrphanReport(orphans)
+	require.Equal(t, maxReportedOrphans+5, report.Total)
+	require.Len(t, report.Resources, maxReportedOrphans)
+	require.Contains(t, orphanResourcesError(orphans).Error(), "and 5 more")
 }
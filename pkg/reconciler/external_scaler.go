@@ -0,0 +1,273 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	externalScalerNamespace         = "kyma-system"
+	externalScalerNamePrefix        = "keda-external-scaler-"
+	externalScalerComponent         = "external-scaler"
+	externalScalerCertsPath         = "/etc/keda-external-scaler/certs"
+	defaultExternalScalerPort int32 = 9090
+)
+
+// sFnUpdateExternalScalers appends the resources of the configured external scalers to the applied objects;
+// the resources of the removed scalers are pruned with the rest of the inventory
+func sFnUpdateExternalScalers(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	objs, err := buildExternalScalersObjs(&s.instance)
+	if err != nil {
+		s.instance.UpdateStateFromErr(
+			v1alpha1.ConditionTypeInstalled,
+			v1alpha1.ConditionReasonExternalScalerErr,
+			err,
+		)
+		return stopWithErrorAndNoRequeue(err)
+	}
+
+	r.Objs = append(r.Objs, objs...)
+	return switchState(sFnUpdateWatchNamespaces)
+}
+
+// buildExternalScalersObjs returns the Deployment, Service and NetworkPolicies of every external scaler;
+// the scalers use the priority class of keda-operator
+func buildExternalScalersObjs(k *v1alpha1.Keda) ([]unstructured.Unstructured, error) {
+	var result []unstructured.Unstructured
+	for _, scaler := range k.Spec.ExternalScalers {
+		for _, obj := range []interface{}{
+			buildExternalScalerDeployment(scaler, *priorityClassNameOperator(k)),
+			buildExternalScalerService(scaler),
+			buildExternalScalerNetworkPolicyFromOperator(scaler),
+			buildOperatorNetworkPolicyToExternalScaler(scaler),
+		} {
+			u, err := toUnstructed(obj)
+			if err != nil {
+				return nil, fmt.Errorf("external scaler %s: %w", scaler.Name, err)
+			}
+			unstructured.RemoveNestedField(u, "status")
+			result = append(result, unstructured.Unstructured{Object: u})
+		}
+	}
+	return result, nil
+}
+
+func externalScalerName(scaler v1alpha1.ExternalScaler) string {
+	return externalScalerNamePrefix + scaler.Name
+}
+
+func externalScalerPort(scaler v1alpha1.ExternalScaler) int32 {
+	if scaler.Port == 0 {
+		return defaultExternalScalerPort
+	}
+	return scaler.Port
+}
+
+func externalScalerSelector(scaler v1alpha1.ExternalScaler) map[string]string {
+	return map[string]string{"app": externalScalerName(scaler)}
+}
+
+func externalScalerLabels(scaler v1alpha1.ExternalScaler) map[string]string {
+	return map[string]string{
+		"app":                         externalScalerName(scaler),
+		"app.kubernetes.io/name":      externalScalerName(scaler),
+		"app.kubernetes.io/component": externalScalerComponent,
+	}
+}
+
+func buildExternalScalerDeployment(scaler v1alpha1.ExternalScaler, priorityClassName string) *appsv1.Deployment {
+	podLabels := setCommonLabels(externalScalerLabels(scaler))
+	podLabels["sidecar.istio.io/inject"] = "false"
+
+	container := corev1.Container{
+		Name:  externalScalerComponent,
+		Image: scaler.Image,
+		Ports: []corev1.ContainerPort{{
+			Name:          "grpc",
+			ContainerPort: externalScalerPort(scaler),
+			Protocol:      corev1.ProtocolTCP,
+		}},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString("grpc")},
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+	}
+	if scaler.Resources != nil {
+		container.Resources = *scaler.Resources
+	}
+
+	podSpec := corev1.PodSpec{
+		Containers:        []corev1.Container{container},
+		PriorityClassName: priorityClassName,
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot: ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+	if scaler.TLSSecret != "" {
+		podSpec.Volumes = []corev1.Volume{{
+			Name: "certs",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: scaler.TLSSecret},
+			},
+		}}
+		podSpec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+			Name:      "certs",
+			MountPath: externalScalerCertsPath,
+			ReadOnly:  true,
+		}}
+	}
+
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalScalerName(scaler),
+			Namespace: externalScalerNamespace,
+			Labels:    externalScalerLabels(scaler),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: externalScalerSelector(scaler)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec:       podSpec,
+			},
+		},
+	}
+}
+
+func buildExternalScalerService(scaler v1alpha1.ExternalScaler) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalScalerName(scaler),
+			Namespace: externalScalerNamespace,
+			Labels:    externalScalerLabels(scaler),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: externalScalerSelector(scaler),
+			Ports: []corev1.ServicePort{{
+				Name:       "grpc",
+				Port:       externalScalerPort(scaler),
+				TargetPort: intstr.FromString("grpc"),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// buildExternalScalerNetworkPolicyFromOperator allows keda-operator to call the scaler gRPC server
+func buildExternalScalerNetworkPolicyFromOperator(scaler v1alpha1.ExternalScaler) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalScalerNetworkPolicyFromOperatorName(scaler),
+			Namespace: externalScalerNamespace,
+			Labels:    externalScalerNetworkPolicyLabels(scaler, "allow-from-operator"),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: externalScalerSelector(scaler)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": operatorName}},
+				}},
+				Ports: externalScalerNetworkPolicyPorts(scaler),
+			}},
+		},
+	}
+}
+
+// buildOperatorNetworkPolicyToExternalScaler allows the egress traffic from keda-operator to the scaler
+func buildOperatorNetworkPolicyToExternalScaler(scaler v1alpha1.ExternalScaler) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorNetworkPolicyToExternalScalerName(scaler),
+			Namespace: externalScalerNamespace,
+			Labels:    externalScalerNetworkPolicyLabels(scaler, "allow-to-external-scaler"),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": operatorName}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{{
+				To: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: externalScalerSelector(scaler)},
+				}},
+				Ports: externalScalerNetworkPolicyPorts(scaler),
+			}},
+		},
+	}
+}
+
+func externalScalerNetworkPolicyFromOperatorName(scaler v1alpha1.ExternalScaler) string {
+	return fmt.Sprintf("kyma-project.io--%s-allow-from-operator", externalScalerName(scaler))
+}
+
+func operatorNetworkPolicyToExternalScalerName(scaler v1alpha1.ExternalScaler) string {
+	return fmt.Sprintf("kyma-project.io--%s-allow-to-%s", operatorName, externalScalerName(scaler))
+}
+
+func externalScalerNetworkPolicyLabels(scaler v1alpha1.ExternalScaler, purpose string) map[string]string {
+	labels := externalScalerLabels(scaler)
+	labels["purpose"] = purpose
+	return labels
+}
+
+func externalScalerNetworkPolicyPorts(scaler v1alpha1.ExternalScaler) []networkingv1.NetworkPolicyPort {
+	port := intstr.FromInt32(externalScalerPort(scaler))
+	return []networkingv1.NetworkPolicyPort{{
+		Protocol: ptr.To(corev1.ProtocolTCP),
+		Port:     &port,
+	}}
+}
+
+// fixExternalScalerObjects returns the objects identifying the resources of the external scaler
+func fixExternalScalerObjects(scaler v1alpha1.ExternalScaler) []unstructured.Unstructured {
+	var objs []unstructured.Unstructured
+	for _, obj := range []struct{ apiVersion, kind, name string }{
+		{"apps/v1", "Deployment", externalScalerName(scaler)},
+		{"v1", "Service", externalScalerName(scaler)},
+		{"networking.k8s.io/v1", "NetworkPolicy", externalScalerNetworkPolicyFromOperatorName(scaler)},
+		{"networking.k8s.io/v1", "NetworkPolicy", operatorNetworkPolicyToExternalScalerName(scaler)},
+	} {
+		objs = append(objs, inventoryObj(v1alpha1.InventoryEntry{
+			APIVersion: obj.apiVersion,
+			Kind:       obj.kind,
+			Namespace:  externalScalerNamespace,
+			Name:       obj.name,
+		}))
+	}
+	return objs
+}
@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnUpdateExternalScalers(t *testing.T) {
+	t.Run("append scaler resources", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{Objs: []unstructured.Unstructured{fixPDBTestDeployment(operatorName)}},
+		}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{
+					ExternalScalers: []v1alpha1.ExternalScaler{
+						{Name: "queue", Image: "example.com/queue-scaler:1.0.0", Port: 6000},
+						{Name: "db", Image: "example.com/db-scaler:1.0.0"},
+					},
+				},
+			},
+		}
+
+		next, result, err := sFnUpdateExternalScalers(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateWatchNamespaces, next)
+
+		require.Len(t, r.Objs, 9)
+		require.Equal(t, operatorName, r.Objs[0].GetName())
+		for i, kind := range []string{"Deployment", "Service", "NetworkPolicy", "NetworkPolicy"} {
+			require.Equal(t, kind, r.Objs[1+i].GetKind())
+			require.Equal(t, kind, r.Objs[5+i].GetKind())
+		}
+		require.Equal(t, "keda-external-scaler-queue", r.Objs[1].GetName())
+		require.Equal(t, "keda-external-scaler-db", r.Objs[5].GetName())
+		for _, obj := range r.Objs[1:] {
+			require.Equal(t, "kyma-system", obj.GetNamespace())
+			require.NotContains(t, obj.Object, "status")
+		}
+	})
+
+	t.Run("no scalers", func(t *testing.T) {
+		r := &fsm{log: zap.NewNop().Sugar()}
+		s := &systemState{}
+
+		next, result, err := sFnUpdateExternalScalers(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateWatchNamespaces, next)
+		require.Empty(t, r.Objs)
+	})
+}
+
+func Test_buildExternalScalerDeployment(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		deployment := buildExternalScalerDeployment(v1alpha1.ExternalScaler{
+			Name:  "queue",
+			Image: "example.com/queue-scaler:1.0.0",
+		}, v1alpha1.DefaultPriorityClassName)
+
+		require.Equal(t, "keda-external-scaler-queue", deployment.GetName())
+		require.Equal(t, map[string]string{"app": "keda-external-scaler-queue"}, deployment.Spec.Selector.MatchLabels)
+		require.Equal(t, "keda-external-scaler-queue", deployment.Spec.Template.Labels["app"])
+		require.Equal(t, "keda-manager", deployment.Spec.Template.Labels[managedByLabel])
+		require.Equal(t, v1alpha1.DefaultPriorityClassName, deployment.Spec.Template.Spec.PriorityClassName)
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		require.Equal(t, "example.com/queue-scaler:1.0.0", container.Image)
+		require.Equal(t, int32(9090), container.Ports[0].ContainerPort)
+		require.Empty(t, container.Resources)
+		require.Empty(t, container.VolumeMounts)
+		require.Empty(t, deployment.Spec.Template.Spec.Volumes)
+	})
+
+	t.Run("resources and TLS secret", func(t *testing.T) {
+		resources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}
+		deployment := buildExternalScalerDeployment(v1alpha1.ExternalScaler{
+			Name:      "queue",
+			Image:     "example.com/queue-scaler:1.0.0",
+			Port:      6000,
+			Resources: &resources,
+			TLSSecret: "queue-scaler-certs",
+		}, "custom-priority")
+
+		podSpec := deployment.Spec.Template.Spec
+		require.Equal(t, "custom-priority", podSpec.PriorityClassName)
+		require.Equal(t, int32(6000), podSpec.Containers[0].Ports[0].ContainerPort)
+		require.Equal(t, resources, podSpec.Containers[0].Resources)
+		require.Equal(t, "queue-scaler-certs", podSpec.Volumes[0].Secret.SecretName)
+		require.Equal(t, externalScalerCertsPath, podSpec.Containers[0].VolumeMounts[0].MountPath)
+		require.True(t, podSpec.Containers[0].VolumeMounts[0].ReadOnly)
+	})
+}
+
+func Test_buildExternalScalerNetworkPolicies(t *testing.T) {
+	scaler := v1alpha1.ExternalScaler{Name: "queue", Image: "example.com/queue-scaler:1.0.0", Port: 6000}
+
+	t.Run("ingress from keda-operator", func(t *testing.T) {
+		np := buildExternalScalerNetworkPolicyFromOperator(scaler)
+
+		require.Equal(t, "kyma-project.io--keda-external-scaler-queue-allow-from-operator", np.GetName())
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+		require.Equal(t, "keda-external-scaler-queue", np.Spec.PodSelector.MatchLabels["app"])
+		require.Equal(t, operatorName, np.Spec.Ingress[0].From[0].PodSelector.MatchLabels["app"])
+		require.Equal(t, int32(6000), np.Spec.Ingress[0].Ports[0].Port.IntVal)
+	})
+
+	t.Run("egress from keda-operator", func(t *testing.T) {
+		np := buildOperatorNetworkPolicyToExternalScaler(scaler)
+
+		require.Equal(t, "kyma-project.io--keda-operator-allow-to-keda-external-scaler-queue", np.GetName())
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, np.Spec.PolicyTypes)
+		require.Equal(t, operatorName, np.Spec.PodSelector.MatchLabels["app"])
+		require.Equal(t, "keda-external-scaler-queue", np.Spec.Egress[0].To[0].PodSelector.MatchLabels["app"])
+		require.Equal(t, int32(6000), np.Spec.Egress[0].Ports[0].Port.IntVal)
+	})
+}
+
+func Test_deleteExternalScalers(t *testing.T) {
+	scaler := v1alpha1.ExternalScaler{Name: "queue", Image: "example.com/queue-scaler:1.0.0"}
+	deployment := buildExternalScalerDeployment(scaler, v1alpha1.DefaultPriorityClassName)
+	service := buildExternalScalerService(scaler)
+	fromOperator := buildExternalScalerNetworkPolicyFromOperator(scaler)
+	toScaler := buildOperatorNetworkPolicyToExternalScaler(scaler)
+	c := fake.NewClientBuilder().WithObjects(deployment, service, fromOperator, toScaler).Build()
+
+	r := &fsm{log: zap.NewNop().Sugar(), K8s: K8s{Client: c}}
+	r.AddExternalScalerObjs([]v1alpha1.ExternalScaler{scaler})
+
+	require.NoError(t, deleteResources(context.Background(), r, r.Objs, nil))
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+		err := c.Get(context.Background(), client.ObjectKeyFromObject(deployment), obj)
+		require.True(t, apierrors.IsNotFound(err))
+	}
+	for _, np := range []*networkingv1.NetworkPolicy{fromOperator, toScaler} {
+		err := c.Get(context.Background(), client.ObjectKeyFromObject(np), &networkingv1.NetworkPolicy{})
+		require.True(t, apierrors.IsNotFound(err))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/addon"
 	"github.com/kyma-project/keda-manager/pkg/reconciler/api"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
@@ -33,12 +34,17 @@ type Cfg struct {
 	// the objects are module component parts; objects are applied
 	// on the cluster one by one with given order
 	Objs []unstructured.Unstructured
-	// AddonObjs holds the HTTP add-on resources fetched at runtime.
-	// They are tracked here so they can be deleted when the addon is disabled or the CR is deleted.
-	AddonObjs []unstructured.Unstructured
+	// Addons lists the add-ons managed next to the module, they are reconciled in the given order
+	Addons []Addon
+	// AddonObjs holds the add-on resources applied in this reconciliation per add-on name
+	AddonObjs map[string][]unstructured.Unstructured
 	// HTTPClient is used for fetching addon manifests from GitHub.
 	// It should be configured with the appropriate TLS trust store.
 	HTTPClient *http.Client
+	// AddonCache keeps the downloaded addon manifests between reconciliations
+	AddonCache *addon.Cache
+	// AddonEndpoints overrides the GitHub URLs the HTTP add-on releases are downloaded from
+	AddonEndpoints addon.Endpoints
 }
 
 var (
@@ -147,6 +153,34 @@ func updateDeploymentPriorityClass(deployment *appsv1.Deployment, priorityClassN
 	return nil
 }
 
+func updateDeploymentReplicas(deployment *appsv1.Deployment, replicas int32) error {
+	deployment.Spec.Replicas = &replicas
+	return nil
+}
+
+func updateDeploymentScheduling(deployment *appsv1.Deployment, scheduling v1alpha1.SchedulingCfg) error {
+	podSpec := &deployment.Spec.Template.Spec
+	// Merge node selectors so we don't clear the ones set in the chart (e.g. kubernetes.io/os)
+	if len(scheduling.NodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		for k, v := range scheduling.NodeSelector {
+			podSpec.NodeSelector[k] = v
+		}
+	}
+	if scheduling.Tolerations != nil {
+		podSpec.Tolerations = scheduling.Tolerations
+	}
+	if scheduling.Affinity != nil {
+		podSpec.Affinity = scheduling.Affinity
+	}
+	if scheduling.TopologySpreadConstraints != nil {
+		podSpec.TopologySpreadConstraints = scheduling.TopologySpreadConstraints
+	}
+	return nil
+}
+
 func updateKedaOperatorContainer0Args(deployment *appsv1.Deployment, logCfg v1alpha1.LoggingCommonCfg) error {
 	logCfg.Sanitize()
 	return updateDeploymentContainer0Args(deployment, &logCfg)
@@ -199,6 +233,15 @@ type systemState struct {
 	objs []unstructured.Unstructured
 
 	snapshot v1alpha1.Status
+
+	// deletions skipped in plan mode, reported in the plan
+	plannedDeletions []v1alpha1.PlannedObjectChange
+
+	// the add-on reconciled by the add-on state functions, the add-ons
+	// are reconciled one by one and the earliest requeue is kept
+	addon        Addon
+	addonIndex   int
+	addonsResult *ctrl.Result
 }
 
 func (s *systemState) saveKedaStatus() {
@@ -227,6 +270,14 @@ var (
 		return u.GetKind() == "Deployment" &&
 			u.GetAPIVersion() == "apps/v1"
 	}
+	isAPIService predicate = func(u unstructured.Unstructured) bool {
+		return u.GetKind() == "APIService" &&
+			u.GroupVersionKind().Group == "apiregistration.k8s.io"
+	}
+	isValidatingWebhookConfiguration predicate = func(u unstructured.Unstructured) bool {
+		return u.GetKind() == "ValidatingWebhookConfiguration" &&
+			u.GroupVersionKind().Group == "admissionregistration.k8s.io"
+	}
 	isWebhookNetworkPolicy predicate = func(u unstructured.Unstructured) bool {
 		return u.GetKind() == "NetworkPolicy" &&
 			u.GetAPIVersion() == "networking.k8s.io/v1" &&
@@ -316,7 +367,32 @@ func (m *fsm) AddLeaseObjs() {
 	m.Objs = append(m.Objs, kedaManagerLease, kedaOperatorLease)
 }
 
+func (m *fsm) AddPodDisruptionBudgetObjs() {
+	for _, name := range []string{operatorName, matricsServerName, admissionWebhooksName} {
+		m.Objs = append(m.Objs, fixPodDisruptionBudgetObject(name, "kyma-system"))
+	}
+}
+
+func (m *fsm) AddExternalScalerObjs(scalers []v1alpha1.ExternalScaler) {
+	for _, scaler := range scalers {
+		m.Objs = append(m.Objs, fixExternalScalerObjects(scaler)...)
+	}
+}
+
+func (m *fsm) AddWatchNamespaceObjs(namespaces []v1alpha1.WatchNamespace) {
+	for _, ns := range namespaces {
+		m.Objs = append(m.Objs, fixWatchNamespaceObjects(ns)...)
+	}
+}
+
+func (m *fsm) AddPriorityClassObj() {
+	m.Objs = append(m.Objs, fixPriorityClassObject())
+}
+
 func NewFsm(log *zap.SugaredLogger, cfg Cfg, k8s K8s) Fsm {
+	// the objects are updated with the Keda CR in place; every reconciliation starts from a copy of the manifest,
+	// so the settings removed from the Keda CR fall back to the manifest defaults instead of the previously applied values
+	cfg.Objs = deepCopyObjs(cfg.Objs)
 	return &fsm{
 		fn:  sFnServedFilter,
 		Cfg: cfg,
@@ -324,3 +400,11 @@ func NewFsm(log *zap.SugaredLogger, cfg Cfg, k8s K8s) Fsm {
 		K8s: k8s,
 	}
 }
+
+func deepCopyObjs(objs []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, *obj.DeepCopy())
+	}
+	return result
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	apirt "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 )
 
 func Test_updateObj_convert_errors(t *testing.T) {
@@ -54,6 +56,12 @@ func Test_updateObj_convert_errors(t *testing.T) {
 		},
 	}
 
+	defaultToUnstructed, defaultFromUnstructured := toUnstructed, fromUnstructured
+	defer func() {
+		toUnstructed = defaultToUnstructed
+		fromUnstructured = defaultFromUnstructured
+	}()
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			toUnstructed = tt.args.toUnstructed
@@ -213,3 +221,90 @@ func Test_updateDeploymentAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func Test_updateDeploymentReplicas(t *testing.T) {
+	deployment := appsv1.Deployment{}
+
+	err := updateDeploymentReplicas(&deployment, 3)
+	require.NoError(t, err)
+	require.NotNil(t, deployment.Spec.Replicas)
+	require.Equal(t, int32(3), *deployment.Spec.Replicas)
+}
+
+func Test_updateDeploymentScheduling(t *testing.T) {
+	t.Run("merge node selector and override scheduling constraints", func(t *testing.T) {
+		deployment := appsv1.Deployment{}
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+		deployment.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "old"}}
+
+		scheduling := v1alpha1.SchedulingCfg{
+			NodeSelector: map[string]string{"pool": "system"},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "system", Effect: corev1.TaintEffectNoSchedule},
+			},
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{},
+			},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		}
+
+		err := updateDeploymentScheduling(&deployment, scheduling)
+		require.NoError(t, err)
+
+		podSpec := deployment.Spec.Template.Spec
+		require.Equal(t, map[string]string{"kubernetes.io/os": "linux", "pool": "system"}, podSpec.NodeSelector)
+		require.Equal(t, scheduling.Tolerations, podSpec.Tolerations)
+		require.Equal(t, scheduling.Affinity, podSpec.Affinity)
+		require.Equal(t, scheduling.TopologySpreadConstraints, podSpec.TopologySpreadConstraints)
+	})
+
+	t.Run("keep chart values when not configured", func(t *testing.T) {
+		deployment := appsv1.Deployment{}
+		deployment.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+		deployment.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "chart"}}
+
+		err := updateDeploymentScheduling(&deployment, v1alpha1.SchedulingCfg{})
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, deployment.Spec.Template.Spec.NodeSelector)
+		require.Equal(t, []corev1.Toleration{{Key: "chart"}}, deployment.Spec.Template.Spec.Tolerations)
+		require.Nil(t, deployment.Spec.Template.Spec.Affinity)
+	})
+}
+
+func Test_NewFsm_startsFromManifest(t *testing.T) {
+	manifestDeployment := appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: operatorName},
+	}
+	manifestDeployment.Spec.Replicas = ptr.To[int32](1)
+	manifestDeployment.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "linux"}
+	manifestDeployment.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "chart"}}
+
+	obj, err := apirt.DefaultUnstructuredConverter.ToUnstructured(&manifestDeployment)
+	require.NoError(t, err)
+	cfg := Cfg{Objs: []unstructured.Unstructured{{Object: obj}}}
+
+	// first reconciliation applies the settings from the Keda CR
+	first := NewFsm(zap.NewNop().Sugar(), cfg, K8s{}).(*fsm)
+	u, err := first.kedaOperatorDeployment()
+	require.NoError(t, err)
+	require.NoError(t, updateObj(u, 3, updateDeploymentReplicas))
+	require.NoError(t, updateObj(u, v1alpha1.SchedulingCfg{
+		NodeSelector: map[string]string{"pool": "system"},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated"}},
+	}, updateDeploymentScheduling))
+
+	// next reconciliation, with the settings removed from the Keda CR, starts from the manifest again
+	next := NewFsm(zap.NewNop().Sugar(), cfg, K8s{}).(*fsm)
+	u, err = next.kedaOperatorDeployment()
+	require.NoError(t, err)
+
+	var deployment appsv1.Deployment
+	require.NoError(t, apirt.DefaultUnstructuredConverter.FromUnstructured(u.Object, &deployment))
+	require.Equal(t, ptr.To[int32](1), deployment.Spec.Replicas)
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, deployment.Spec.Template.Spec.NodeSelector)
+	require.Equal(t, []corev1.Toleration{{Key: "chart"}}, deployment.Spec.Template.Spec.Tolerations)
+}
@@ -0,0 +1,442 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/addon"
+	"github.com/kyma-project/keda-manager/pkg/reconciler/api"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// httpAddon installs the kedacore/http-add-on configured in spec.httpAddon
+type httpAddon struct{}
+
+func (httpAddon) Name() string {
+	return httpAddonName
+}
+
+func (httpAddon) Title() string {
+	return "HTTP add-on"
+}
+
+func (httpAddon) ConditionType() v1alpha1.ConditionType {
+	return v1alpha1.ConditionTypeAddonInstalled
+}
+
+func (httpAddon) ReasonPrefix() string {
+	return v1alpha1.HTTPAddonReasonPrefix
+}
+
+func (httpAddon) Config(instance *v1alpha1.Keda) AddonConfig {
+	cfg := v1alpha1.ReadAddonCfg(instance)
+	return AddonConfig{
+		Enabled:   cfg.Enabled,
+		Namespace: cfg.EffectiveNamespace(),
+		Version:   cfg.Version,
+		Source:    addonSourceFromCfg(instance, cfg),
+	}
+}
+
+func (httpAddon) ResolveVersion(r *fsm, version, installedVersion string) (string, error) {
+	return resolveAddonVersion(r, version, installedVersion)
+}
+
+func (httpAddon) Manifest(ctx context.Context, r *fsm, src addonSource, version string) ([]unstructured.Unstructured, error) {
+	return loadAddonResources(ctx, r, src, version)
+}
+
+func (httpAddon) Patch(objs []unstructured.Unstructured, instance *v1alpha1.Keda, namespace string) {
+	cfg := v1alpha1.ReadAddonCfg(instance)
+	overrideNamespace(objs, namespace, cfg.IstioInjection)
+	overrideComponents(objs, cfg)
+}
+
+func (httpAddon) ExtraResources(instance *v1alpha1.Keda, namespace string) []unstructured.Unstructured {
+	objs := addon.NetworkPolicies(namespace)
+	return attachIstioAddonResources(objs, namespace, v1alpha1.ReadAddonCfg(instance).IstioInjection)
+}
+
+func (httpAddon) OptionalResources(namespace string) []unstructured.Unstructured {
+	return []unstructured.Unstructured{addon.PeerAuthentication(namespace)}
+}
+
+func (httpAddon) InUse(ctx context.Context, c client.Client) ([]unstructured.Unstructured, error) {
+	list, err := listHTTPScaledObjects(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (httpAddon) NotReady(objs []unstructured.Unstructured) ([]string, error) {
+	return addonNotReadyComponents(objs, addonDeploymentPrefix)
+}
+
+// deprecationNotice lists the deprecated addon annotations used in the Keda CR
+func (httpAddon) deprecationNotice(instance *v1alpha1.Keda) string {
+	deprecated := v1alpha1.DeprecatedAddonAnnotations(instance)
+	if len(deprecated) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("annotations %s are deprecated, use spec.httpAddon instead", strings.Join(deprecated, ", "))
+}
+
+// legacyStatus returns the installation recorded in the annotations
+// used before the add-on installations were tracked in status.addons
+func (httpAddon) legacyStatus(instance *v1alpha1.Keda) *v1alpha1.AddonStatus {
+	ann := instance.GetAnnotations()
+	if ann[v1alpha1.AnnotationAddonInstalledVersion] == "" {
+		return nil
+	}
+	return &v1alpha1.AddonStatus{
+		Name:      httpAddonName,
+		Version:   ann[v1alpha1.AnnotationAddonInstalledVersion],
+		Namespace: ann[v1alpha1.AnnotationAddonInstalledNamespace],
+	}
+}
+
+func (httpAddon) clearLegacyStatus(instance *v1alpha1.Keda) bool {
+	ann := instance.GetAnnotations()
+	_, hasVersion := ann[v1alpha1.AnnotationAddonInstalledVersion]
+	_, hasNamespace := ann[v1alpha1.AnnotationAddonInstalledNamespace]
+	v1alpha1.SetAnnotation(instance, v1alpha1.AnnotationAddonInstalledVersion, "")
+	v1alpha1.SetAnnotation(instance, v1alpha1.AnnotationAddonInstalledNamespace, "")
+	return hasVersion || hasNamespace
+}
+
+const (
+	httpAddonName         = "http"
+	addonDeploymentPrefix = "keda-add-ons-http-"
+
+	istioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
+	istioExcludeInboundPortsValue      = "9090"
+	istioSidecarInjectAnnotation       = "sidecar.istio.io/inject"
+	kymaModuleLabel                    = "kyma-project.io/module"
+	kymaModuleLabelValue               = "keda"
+
+	httpScaledObjectGroup   = "http.keda.sh"
+	httpScaledObjectVersion = "v1alpha1"
+	httpScaledObjectKind    = "HTTPScaledObject"
+)
+
+var namespaceEnvVars = map[string]struct{}{
+	"KEDA_HTTP_SCALER_TARGET_ADMIN_NAMESPACE": {},
+	"KEDA_HTTP_OPERATOR_NAMESPACE":            {},
+}
+
+func overrideNamespace(objs []unstructured.Unstructured, namespace string, istioInjection bool) {
+	for i := range objs {
+		obj := &objs[i]
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(namespace)
+		}
+		applyCommonMetadataLabels(obj)
+		switch obj.GetKind() {
+		case "ClusterRoleBinding", "RoleBinding":
+			patchSubjectsNamespace(obj, namespace)
+		case "Deployment":
+			patchDeploymentEnvNamespace(obj, namespace)
+			patchDeploymentPodTemplateLabels(obj)
+			if istioInjection {
+				patchDeploymentIstioExcludePortsAnnotation(obj)
+				patchDeploymentIstioSidecarAnnotation(obj, "true")
+			} else {
+				patchDeploymentIstioSidecarAnnotation(obj, "false")
+			}
+		}
+	}
+}
+
+// applyCommonMetadataLabels stamps the standard Kyma module labels
+// (kyma-project.io/module=keda, app.kubernetes.io/part-of=keda-manager,
+// app.kubernetes.io/managed-by=keda-manager) on the object's top-level
+// metadata so add-on resources (Deployments, Services, etc.) are discoverable
+// via the same label selectors as the rest of the Keda module.
+func applyCommonMetadataLabels(obj *unstructured.Unstructured) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	obj.SetLabels(setCommonLabels(labels))
+}
+
+func patchDeploymentIstioExcludePortsAnnotation(obj *unstructured.Unstructured) {
+	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[istioExcludeInboundPortsAnnotation] == istioExcludeInboundPortsValue {
+		return
+	}
+	annotations[istioExcludeInboundPortsAnnotation] = istioExcludeInboundPortsValue
+	_ = unstructured.SetNestedStringMap(obj.Object, annotations, "spec", "template", "metadata", "annotations")
+}
+
+func patchDeploymentIstioSidecarAnnotation(obj *unstructured.Unstructured, value string) {
+	annotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[istioSidecarInjectAnnotation] == value {
+		return
+	}
+	annotations[istioSidecarInjectAnnotation] = value
+	_ = unstructured.SetNestedStringMap(obj.Object, annotations, "spec", "template", "metadata", "annotations")
+}
+
+// patchDeploymentPodTemplateLabels stamps `kyma-project.io/module=keda` on
+// the Deployment's pod template so add-on Pods (interceptor, operator, scaler)
+// are discoverable via the standard Kyma module label selector.
+func patchDeploymentPodTemplateLabels(obj *unstructured.Unstructured) {
+	labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if labels[kymaModuleLabel] == kymaModuleLabelValue {
+		return
+	}
+	labels[kymaModuleLabel] = kymaModuleLabelValue
+	_ = unstructured.SetNestedStringMap(obj.Object, labels, "spec", "template", "metadata", "labels")
+}
+
+func patchDeploymentEnvNamespace(obj *unstructured.Unstructured, namespace string) {
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found {
+		return
+	}
+	changed := false
+	for ci, rawC := range containers {
+		container, ok := rawC.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		envList, ok := container["env"].([]interface{})
+		if !ok {
+			continue
+		}
+		for ei, rawE := range envList {
+			envVar, ok := rawE.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := envVar["name"].(string)
+			if _, match := namespaceEnvVars[name]; match && envVar["value"] != namespace {
+				envVar["value"] = namespace
+				envList[ei] = envVar
+				changed = true
+			}
+		}
+		container["env"] = envList
+		containers[ci] = container
+	}
+	if changed {
+		_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+	}
+}
+
+func patchSubjectsNamespace(obj *unstructured.Unstructured, namespace string) {
+	subjects, found, err := unstructured.NestedSlice(obj.Object, "subjects")
+	if err != nil || !found {
+		return
+	}
+	changed := false
+	for j, raw := range subjects {
+		subj, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, _, _ := unstructured.NestedString(subj, "kind"); kind != "ServiceAccount" {
+			continue
+		}
+		if ns, _, _ := unstructured.NestedString(subj, "namespace"); ns != "" && ns != namespace {
+			subj["namespace"] = namespace
+			subjects[j] = subj
+			changed = true
+		}
+	}
+	if changed {
+		_ = unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+	}
+}
+
+// addonComponentLabel identifies the add-on component (interceptor, external-scaler, operator) of the Deployment pods
+const addonComponentLabel = "app.kubernetes.io/instance"
+
+// overrideComponents applies the replicas, resources and log level configured for the add-on components
+func overrideComponents(objs []unstructured.Unstructured, cfg v1alpha1.AddonCfg) {
+	components := map[string]*v1alpha1.AddonComponentCfg{
+		"interceptor":     cfg.Interceptor,
+		"external-scaler": cfg.Scaler,
+		"operator":        cfg.Operator,
+	}
+	for i := range objs {
+		obj := &objs[i]
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		component, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "labels", addonComponentLabel)
+		componentCfg := components[component]
+		if componentCfg == nil {
+			continue
+		}
+		if componentCfg.Replicas != nil {
+			_ = unstructured.SetNestedField(obj.Object, int64(*componentCfg.Replicas), "spec", "replicas")
+		}
+		if componentCfg.Resources != nil {
+			patchDeploymentContainer0Resources(obj, *componentCfg.Resources)
+		}
+		if componentCfg.LogLevel != nil {
+			patchDeploymentContainer0Arg(obj, componentCfg.LogLevel)
+		}
+	}
+}
+
+func patchDeploymentContainer0Resources(obj *unstructured.Unstructured, resources corev1.ResourceRequirements) {
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+	converted, err := toUnstructed(&resources)
+	if err != nil {
+		return
+	}
+	container["resources"] = converted
+	containers[0] = container
+	_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
+// patchDeploymentContainer0Arg replaces the matching argument of the first container or appends it if missing
+func patchDeploymentContainer0Arg(obj *unstructured.Unstructured, arg api.MatchStringer) {
+	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	if err != nil || !found || len(containers) == 0 {
+		return
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+	args, _, _ := unstructured.NestedStringSlice(container, "args")
+	replaced := false
+	for i := range args {
+		if arg.Match(&args[i]) {
+			args[i] = arg.String()
+			replaced = true
+		}
+	}
+	if !replaced {
+		args = append(args, arg.String())
+	}
+	_ = unstructured.SetNestedStringSlice(container, args, "args")
+	containers[0] = container
+	_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+}
+
+// addonSourceFromCfg returns the add-on manifests source configured in the Keda CR
+func addonSourceFromCfg(instance *v1alpha1.Keda, cfg v1alpha1.AddonCfg) addonSource {
+	src := addonSource{
+		digests: addon.Digests{CRDs: cfg.CRDsDigest, Manifest: cfg.ManifestDigest},
+	}
+	if cfg.ManifestsConfigMap != "" {
+		src.configMap = client.ObjectKey{Namespace: instance.GetNamespace(), Name: cfg.ManifestsConfigMap}
+	}
+	return src
+}
+
+// loadAddonResources reads the add-on resources from the configured ConfigMap or from the manifests bundled
+// with keda-manager; the manifests are downloaded from GitHub only when the version is not bundled
+func loadAddonResources(ctx context.Context, r *fsm, src addonSource, version string) ([]unstructured.Unstructured, error) {
+	if src.configMap.Name != "" {
+		var cm corev1.ConfigMap
+		if err := r.Get(ctx, src.configMap, &cm); err != nil {
+			return nil, fmt.Errorf("failed to get addon manifests ConfigMap %s: %w", src.configMap, err)
+		}
+		return addon.LoadConfigMapResources(&cm)
+	}
+
+	objs, err := addon.LoadBundledResources(version)
+	if !errors.Is(err, addon.ErrManifestsNotBundled) {
+		return objs, err
+	}
+
+	digests := addon.PinnedDigests(version, src.digests)
+	if src.toDelete && !digests.IsComplete() {
+		// the objects are only deleted, so they are neither verified nor cached for the installation
+		r.log.Infof("addon manifests for version %s are not bundled, downloading them to delete the installed resources", version)
+		return addon.FetchResourcesToDelete(r.HTTPClient, r.AddonEndpoints, version, digests)
+	}
+
+	// digests are part of the key so that a changed override is verified against a new download
+	cacheKey := fmt.Sprintf("%s/%s/%s/%s", httpAddonName, version, digests.CRDs, digests.Manifest)
+	if objs, found := r.AddonCache.Get(cacheKey); found {
+		return objs, nil
+	}
+
+	r.log.Infof("addon manifests for version %s are not bundled, downloading them", version)
+	objs, err = addon.FetchResources(r.HTTPClient, r.AddonEndpoints, version, digests)
+	if err != nil {
+		return nil, err
+	}
+	r.AddonCache.Set(cacheKey, objs)
+	return objs, nil
+}
+
+func attachIstioAddonResources(objs []unstructured.Unstructured, namespace string, istioInjection bool) []unstructured.Unstructured {
+	if istioInjection {
+		objs = append(objs, addon.PeerAuthentication(namespace))
+	}
+	return objs
+}
+
+// listHTTPScaledObjects returns the HTTPScaledObjects on the cluster. A missing CRD (NoKindMatchError / IsNotFound)
+// returns an empty list — there is nothing to block because the type doesn't exist. RBAC / API errors
+// are returned to the caller so they can surface a Warning and requeue.
+func listHTTPScaledObjects(ctx context.Context, c client.Client) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   httpScaledObjectGroup,
+		Version: httpScaledObjectVersion,
+		Kind:    httpScaledObjectKind + "List",
+	})
+	if err := c.List(ctx, list); err != nil {
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return &unstructured.UnstructuredList{}, nil
+		}
+		return nil, fmt.Errorf("list HTTPScaledObjects: %w", err)
+	}
+	return list, nil
+}
+
+// resolveAddonVersion returns the add-on version to install: the bundled default when no version is configured,
+// the newest release for "latest" (or the installed version when the release can't be resolved) and the
+// validated pinned version otherwise
+func resolveAddonVersion(r *fsm, version, installedVersion string) (string, error) {
+	switch version {
+	case "":
+		return v1alpha1.DefaultAddonVersion, nil
+	case v1alpha1.AddonVersionLatest:
+		latest, err := addon.LatestVersion(r.HTTPClient, r.AddonEndpoints)
+		if err != nil && installedVersion != "" {
+			r.log.With("err", err).Warnf("failed to resolve latest addon version, keeping installed version %s", installedVersion)
+			return installedVersion, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve latest addon version: %w", err)
+		}
+		return addon.ValidateVersion(latest)
+	default:
+		return addon.ValidateVersion(version)
+	}
+}
@@ -0,0 +1,27 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// sFnPausedFilter stops the reconciliation of the KEDA resources when the Keda CR has the reconcile-paused
+// annotation, so that manual changes in the cluster are not reverted; deletion of the Keda CR is not paused
+func sFnPausedFilter(_ context.Context, _ *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	instanceIsBeingDeleted := !s.instance.GetDeletionTimestamp().IsZero()
+	if instanceIsBeingDeleted || !s.instance.IsReconcilePaused() {
+		s.instance.RemoveCondition(v1alpha1.ConditionTypePaused)
+		return switchState(sFnInitialize)
+	}
+
+	s.instance.UpdateCondition(
+		v1alpha1.ConditionTypePaused,
+		metav1.ConditionTrue,
+		v1alpha1.ConditionReasonReconcilePaused,
+		"reconciliation is paused, remove the "+v1alpha1.AnnotationReconcilePaused+" annotation to resume it",
+	)
+	return stopWithNoRequeue()
+}
@@ -0,0 +1,60 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_sFnPausedFilter(t *testing.T) {
+	t.Run("stop reconciliation of paused instance", func(t *testing.T) {
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{v1alpha1.AnnotationReconcilePaused: "true"},
+				},
+			},
+		}
+
+		next, result, err := sFnPausedFilter(context.Background(), nil, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypePaused))
+		require.NotNil(t, condition)
+		require.Equal(t, metav1.ConditionTrue, condition.Status)
+		require.Equal(t, string(v1alpha1.ConditionReasonReconcilePaused), condition.Reason)
+	})
+
+	t.Run("resume reconciliation", func(t *testing.T) {
+		s := &systemState{}
+		s.instance.UpdateCondition(v1alpha1.ConditionTypePaused, metav1.ConditionTrue, v1alpha1.ConditionReasonReconcilePaused, "")
+
+		next, result, err := sFnPausedFilter(context.Background(), nil, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnInitialize, next)
+		require.Nil(t, meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypePaused)))
+	})
+
+	t.Run("do not pause deletion", func(t *testing.T) {
+		now := metav1.Now()
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations:       map[string]string{v1alpha1.AnnotationReconcilePaused: "true"},
+					DeletionTimestamp: &now,
+				},
+			},
+		}
+
+		next, _, err := sFnPausedFilter(context.Background(), nil, s)
+		require.NoError(t, err)
+		requireEqualFunc(t, sFnInitialize, next)
+	})
+}
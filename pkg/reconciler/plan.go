@@ -0,0 +1,205 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxPlannedFields caps the number of field paths reported per object to keep the status small
+const maxPlannedFields = 20
+
+// fields set by the api server that are not part of the desired state
+var ignoredPlanFields = map[string]struct{}{
+	"status":                     {},
+	"metadata.managedFields":     {},
+	"metadata.resourceVersion":   {},
+	"metadata.generation":        {},
+	"metadata.creationTimestamp": {},
+	"metadata.uid":               {},
+}
+
+// sFnPlan applies all objects with server-side apply dry-run and reports the difference
+// against the live objects in the Keda CR status without changing the cluster
+func sFnPlan(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	for _, entry := range staleInventory(s.instance.Status.Inventory, r.Objs) {
+		if err := planDeletion(ctx, r, s, inventoryObj(entry)); err != nil {
+			s.instance.UpdateStateFromErr(
+				v1alpha1.ConditionTypePlanned,
+				v1alpha1.ConditionReasonPlanErr,
+				err,
+			)
+			return stopWithNoRequeue()
+		}
+	}
+
+	plan := v1alpha1.PlanStatus{
+		ObjectsChanged: len(s.plannedDeletions),
+		Objects:        s.plannedDeletions,
+	}
+	var planErr error
+	for _, obj := range r.Objs {
+		obj, err := prepareObj(obj)
+		if err != nil {
+			planErr = errors.Join(planErr, err)
+			continue
+		}
+
+		change, err := planObj(ctx, r, obj)
+		if err != nil {
+			r.log.With("err", err).With("name", obj.GetName()).Error("plan error")
+			planErr = errors.Join(planErr, err)
+			continue
+		}
+		if change == nil {
+			continue
+		}
+
+		plan.ObjectsChanged++
+		plan.FieldsChanged += len(change.Fields)
+		plan.Objects = append(plan.Objects, *change)
+	}
+
+	s.instance.Status.Plan = &plan
+	if planErr != nil {
+		s.instance.UpdateStateFromErr(
+			v1alpha1.ConditionTypePlanned,
+			v1alpha1.ConditionReasonPlanErr,
+			planErr,
+		)
+		return stopWithNoRequeue()
+	}
+
+	s.instance.UpdateCondition(
+		v1alpha1.ConditionTypePlanned,
+		metav1.ConditionTrue,
+		v1alpha1.ConditionReasonPlanned,
+		fmt.Sprintf("%d objects would change (%d fields), changes are not applied until reconcilePolicy is set to Apply",
+			plan.ObjectsChanged, plan.FieldsChanged),
+	)
+	return stopWithNoRequeue()
+}
+
+// planObj returns the change the object apply would cause or nil if the object would not change
+func planObj(ctx context.Context, r *fsm, obj unstructured.Unstructured) (*v1alpha1.PlannedObjectChange, error) {
+	change := v1alpha1.PlannedObjectChange{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+
+	live := unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKeyFromObject(&obj), &live)
+	if apierrors.IsNotFound(err) {
+		change.Action = v1alpha1.PlanActionCreate
+		return &change, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := obj.DeepCopy()
+	err = r.Patch(ctx, dryRun, client.Apply, &client.PatchOptions{
+		Force:        ptr.To(true),
+		FieldManager: "keda-manager",
+		DryRun:       []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := diffFields("", live.Object, dryRun.Object)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(fields)
+	if len(fields) > maxPlannedFields {
+		fields = append(fields[:maxPlannedFields], fmt.Sprintf("... and %d more", len(fields)-maxPlannedFields))
+	}
+	change.Action = v1alpha1.PlanActionUpdate
+	change.Fields = fields
+	return &change, nil
+}
+
+// planDeletion records the deletion of the object if it exists on the cluster and is managed by keda-manager
+func planDeletion(ctx context.Context, r *fsm, s *systemState, obj unstructured.Unstructured) error {
+	err := r.Get(ctx, client.ObjectKeyFromObject(&obj), &obj)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if obj.GetLabels()[managedByLabel] != "keda-manager" {
+		return nil
+	}
+
+	s.plannedDeletions = append(s.plannedDeletions, v1alpha1.PlannedObjectChange{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Action:     v1alpha1.PlanActionDelete,
+	})
+	return nil
+}
+
+// diffFields returns the paths of the fields that differ between the live and the desired object
+func diffFields(path string, live, desired interface{}) []string {
+	if _, ignored := ignoredPlanFields[path]; ignored {
+		return nil
+	}
+
+	liveMap, liveIsMap := live.(map[string]interface{})
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	if liveIsMap && desiredIsMap {
+		var fields []string
+		keys := map[string]struct{}{}
+		for k := range liveMap {
+			keys[k] = struct{}{}
+		}
+		for k := range desiredMap {
+			keys[k] = struct{}{}
+		}
+		for k := range keys {
+			fields = append(fields, diffFields(joinPath(path, k), liveMap[k], desiredMap[k])...)
+		}
+		return fields
+	}
+
+	liveSlice, liveIsSlice := live.([]interface{})
+	desiredSlice, desiredIsSlice := desired.([]interface{})
+	if liveIsSlice && desiredIsSlice && len(liveSlice) == len(desiredSlice) {
+		var fields []string
+		for i := range liveSlice {
+			fields = append(fields, diffFields(fmt.Sprintf("%s[%d]", path, i), liveSlice[i], desiredSlice[i])...)
+		}
+		return fields
+	}
+
+	if reflect.DeepEqual(live, desired) {
+		return nil
+	}
+	return []string{path}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
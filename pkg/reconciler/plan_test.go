@@ -0,0 +1,111 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_diffFields(t *testing.T) {
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "test",
+			"resourceVersion": "1",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"ports":    []interface{}{int64(80), int64(443)},
+		},
+		"status": map[string]interface{}{
+			"readyReplicas": int64(1),
+		},
+	}
+	desired := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "test",
+			"resourceVersion": "2",
+			"labels":          map[string]interface{}{"app": "test"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"ports":    []interface{}{int64(80), int64(8443)},
+		},
+	}
+
+	require.ElementsMatch(t, []string{
+		"metadata.labels",
+		"spec.replicas",
+		"spec.ports[1]",
+	}, diffFields("", live, desired))
+	require.Empty(t, diffFields("", live, live))
+}
+
+func Test_sFnPlan(t *testing.T) {
+	t.Run("report missing objects and planned deletions without applying", func(t *testing.T) {
+		svc := unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "Service",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name":      "test-service",
+					"namespace": "test",
+					"labels":    map[string]interface{}{"app": "test"},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{svc}},
+		}
+		deletion := v1alpha1.PlannedObjectChange{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+			Namespace:  "kyma-system",
+			Name:       operatorName,
+			Action:     v1alpha1.PlanActionDelete,
+		}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{ReconcilePolicy: v1alpha1.ReconcilePolicyPlan},
+			},
+			plannedDeletions: []v1alpha1.PlannedObjectChange{deletion},
+		}
+
+		next, result, err := sFnPlan(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+
+		plan := s.instance.Status.Plan
+		require.NotNil(t, plan)
+		require.Equal(t, 2, plan.ObjectsChanged)
+		require.Equal(t, deletion, plan.Objects[0])
+		require.Equal(t, v1alpha1.PlanActionCreate, plan.Objects[1].Action)
+		require.Equal(t, svc.GetName(), plan.Objects[1].Name)
+		require.False(t, canGetFakeResource(c, svc))
+
+		condition := s.instance.Status.Conditions[0]
+		require.Equal(t, string(v1alpha1.ConditionTypePlanned), condition.Type)
+		require.Equal(t, string(v1alpha1.ConditionReasonPlanned), condition.Reason)
+	})
+}
+
+func Test_sFnApply_planMode(t *testing.T) {
+	s := &systemState{
+		instance: v1alpha1.Keda{
+			Spec: v1alpha1.KedaSpec{ReconcilePolicy: v1alpha1.ReconcilePolicyPlan},
+		},
+	}
+
+	next, result, err := sFnApply(context.Background(), &fsm{}, s)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	requireEqualFunc(t, sFnPlan, next)
+}
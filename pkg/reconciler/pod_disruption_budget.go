@@ -0,0 +1,177 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type pdbComponent struct {
+	isDeployment predicate
+	cfg          func(*v1alpha1.Keda) *v1alpha1.PodDisruptionBudgetCfg
+}
+
+var pdbComponents = []pdbComponent{
+	{isDeployment: isKedaOperatorDeployment, cfg: pdbOperatorCfg},
+	{isDeployment: isKedaMatricsServerDeployment, cfg: pdbMetricsSvrCfg},
+	{isDeployment: isAdmissionWebhooksDeployment, cfg: pdbAdmissionWebhookCfg},
+}
+
+// sFnUpdatePodDisruptionBudgets appends a PodDisruptionBudget to the applied objects for every
+// component with PodDisruptionBudget configuration and removes the ones that are no longer configured
+func sFnUpdatePodDisruptionBudgets(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	for _, component := range pdbComponents {
+		u, err := r.firstUnstructed(component.isDeployment)
+		if err != nil {
+			s.instance.UpdateStateFromErr(
+				v1alpha1.ConditionTypeInstalled,
+				v1alpha1.ConditionReasonPDBUpdateErr,
+				err,
+			)
+			return stopWithErrorAndNoRequeue(err)
+		}
+
+		cfg := component.cfg(&s.instance)
+		if cfg == nil {
+			pdb := fixPodDisruptionBudgetObject(u.GetName(), u.GetNamespace())
+			if s.instance.Spec.IsPlanMode() {
+				if err := planDeletion(ctx, r, s, pdb); err != nil {
+					s.instance.UpdateStateFromErr(
+						v1alpha1.ConditionTypePlanned,
+						v1alpha1.ConditionReasonPlanErr,
+						err,
+					)
+					return stopWithErrorAndNoRequeue(err)
+				}
+				continue
+			}
+			if err := deletePodDisruptionBudget(ctx, r, pdb); err != nil {
+				s.instance.UpdateStateFromErr(
+					v1alpha1.ConditionTypeInstalled,
+					v1alpha1.ConditionReasonPDBUpdateErr,
+					err,
+				)
+				return stopWithErrorAndNoRequeue(err)
+			}
+			continue
+		}
+
+		pdb, err := buildPodDisruptionBudget(*u, *cfg)
+		if err != nil {
+			s.instance.UpdateStateFromErr(
+				v1alpha1.ConditionTypeInstalled,
+				v1alpha1.ConditionReasonPDBUpdateErr,
+				err,
+			)
+			return stopWithErrorAndNoRequeue(err)
+		}
+		r.Objs = append(r.Objs, pdb)
+	}
+
+	return switchState(sFnUpdateExternalScalers)
+}
+
+// buildPodDisruptionBudget creates PodDisruptionBudget selecting the pods of the given deployment;
+// maxUnavailable=1 is used when neither minAvailable nor maxUnavailable is configured
+func buildPodDisruptionBudget(u unstructured.Unstructured, cfg v1alpha1.PodDisruptionBudgetCfg) (unstructured.Unstructured, error) {
+	var deployment appsv1.Deployment
+	if err := fromUnstructured(u.Object, &deployment); err != nil {
+		return unstructured.Unstructured{}, err
+	}
+
+	if deployment.Spec.Selector == nil {
+		return unstructured.Unstructured{}, fmt.Errorf("deployment %s/%s has no selector", u.GetNamespace(), u.GetName())
+	}
+
+	if cfg.MinAvailable == nil && cfg.MaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt32(1)
+		cfg.MaxUnavailable = &maxUnavailable
+	}
+
+	pdb := policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployment.GetName(),
+			Namespace: deployment.GetNamespace(),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   cfg.MinAvailable,
+			MaxUnavailable: cfg.MaxUnavailable,
+			Selector:       deployment.Spec.Selector.DeepCopy(),
+		},
+	}
+
+	obj, err := toUnstructed(&pdb)
+	if err != nil {
+		return unstructured.Unstructured{}, err
+	}
+
+	result := unstructured.Unstructured{Object: obj}
+	unstructured.RemoveNestedField(result.Object, "status")
+	return result, nil
+}
+
+func fixPodDisruptionBudgetObject(name, namespace string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "PodDisruptionBudget",
+			"apiVersion": "policy/v1",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+			},
+		},
+	}
+}
+
+// deletePodDisruptionBudget removes the PodDisruptionBudget only when it exists,
+// so that components without PodDisruptionBudget configuration do not cost a Delete call on every reconciliation
+func deletePodDisruptionBudget(ctx context.Context, r *fsm, pdb unstructured.Unstructured) error {
+	err := r.Get(ctx, client.ObjectKeyFromObject(&pdb), &pdb)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = r.Delete(ctx, &pdb)
+	if client.IgnoreNotFound(err) == nil || meta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+func pdbOperatorCfg(k *v1alpha1.Keda) *v1alpha1.PodDisruptionBudgetCfg {
+	if k != nil && k.Spec.PodDisruptionBudget != nil {
+		return k.Spec.PodDisruptionBudget.Operator
+	}
+	return nil
+}
+
+func pdbMetricsSvrCfg(k *v1alpha1.Keda) *v1alpha1.PodDisruptionBudgetCfg {
+	if k != nil && k.Spec.PodDisruptionBudget != nil {
+		return k.Spec.PodDisruptionBudget.MetricsServer
+	}
+	return nil
+}
+
+func pdbAdmissionWebhookCfg(k *v1alpha1.Keda) *v1alpha1.PodDisruptionBudgetCfg {
+	if k != nil && k.Spec.PodDisruptionBudget != nil {
+		return k.Spec.PodDisruptionBudget.AdmissionWebhook
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func fixPDBTestDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "Deployment",
+			"apiVersion": "apps/v1",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "kyma-system",
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"app": name,
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_buildPodDisruptionBudget(t *testing.T) {
+	t.Run("use deployment selector and configured minAvailable", func(t *testing.T) {
+		minAvailable := intstr.FromInt32(2)
+
+		u, err := buildPodDisruptionBudget(fixPDBTestDeployment(operatorName), v1alpha1.PodDisruptionBudgetCfg{
+			MinAvailable: &minAvailable,
+		})
+		require.NoError(t, err)
+
+		var pdb policyv1.PodDisruptionBudget
+		require.NoError(t, fromUnstructured(u.Object, &pdb))
+		require.Equal(t, "PodDisruptionBudget", pdb.Kind)
+		require.Equal(t, "policy/v1", pdb.APIVersion)
+		require.Equal(t, operatorName, pdb.GetName())
+		require.Equal(t, "kyma-system", pdb.GetNamespace())
+		require.Equal(t, map[string]string{"app": operatorName}, pdb.Spec.Selector.MatchLabels)
+		require.Equal(t, &minAvailable, pdb.Spec.MinAvailable)
+		require.Nil(t, pdb.Spec.MaxUnavailable)
+	})
+
+	t.Run("default to maxUnavailable 1", func(t *testing.T) {
+		u, err := buildPodDisruptionBudget(fixPDBTestDeployment(operatorName), v1alpha1.PodDisruptionBudgetCfg{})
+		require.NoError(t, err)
+
+		var pdb policyv1.PodDisruptionBudget
+		require.NoError(t, fromUnstructured(u.Object, &pdb))
+		require.Nil(t, pdb.Spec.MinAvailable)
+		require.Equal(t, intstr.FromInt32(1), *pdb.Spec.MaxUnavailable)
+	})
+
+	t.Run("deployment without selector", func(t *testing.T) {
+		u := fixPDBTestDeployment(operatorName)
+		unstructured.RemoveNestedField(u.Object, "spec", "selector")
+
+		_, err := buildPodDisruptionBudget(u, v1alpha1.PodDisruptionBudgetCfg{})
+		require.Error(t, err)
+	})
+}
+
+func Test_sFnUpdatePodDisruptionBudgets(t *testing.T) {
+	t.Run("append configured and delete not configured budgets", func(t *testing.T) {
+		stalePDB := fixPodDisruptionBudgetObject(admissionWebhooksName, "kyma-system")
+		c := fake.NewClientBuilder().WithObjects(&stalePDB).Build()
+		maxUnavailable := intstr.FromString("50%")
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{
+				fixPDBTestDeployment(operatorName),
+				fixPDBTestDeployment(matricsServerName),
+				fixPDBTestDeployment(admissionWebhooksName),
+			}},
+		}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{
+					PodDisruptionBudget: &v1alpha1.PodDisruptionBudgets{
+						MetricsServer: &v1alpha1.PodDisruptionBudgetCfg{
+							MaxUnavailable: &maxUnavailable,
+						},
+					},
+				},
+			},
+		}
+
+		next, result, err := sFnUpdatePodDisruptionBudgets(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateExternalScalers, next)
+
+		require.Len(t, r.Objs, 4)
+		require.Equal(t, "PodDisruptionBudget", r.Objs[3].GetKind())
+		require.Equal(t, matricsServerName, r.Objs[3].GetName())
+		require.False(t, canGetFakeResource(c, stalePDB))
+	})
+
+	t.Run("skip deleting not existing budgets", func(t *testing.T) {
+		deletes := 0
+		c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{
+				fixPDBTestDeployment(operatorName),
+				fixPDBTestDeployment(matricsServerName),
+				fixPDBTestDeployment(admissionWebhooksName),
+			}},
+		}
+		s := &systemState{}
+
+		next, result, err := sFnUpdatePodDisruptionBudgets(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateExternalScalers, next)
+		require.Len(t, r.Objs, 3)
+		require.Zero(t, deletes)
+	})
+
+	t.Run("missing deployment", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: fake.NewClientBuilder().Build()},
+		}
+		s := &systemState{}
+
+		next, result, err := sFnUpdatePodDisruptionBudgets(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateStatus(&ctrl.Result{}, err), next)
+		require.Equal(t, v1alpha1.StateError, s.instance.Status.State)
+	})
+}
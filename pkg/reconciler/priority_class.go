@@ -0,0 +1,81 @@
+package reconciler
+
+import (
+	"context"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultPriorityClassValue int64 = 2000000
+
+// sFnUpdatePriorityClass prepends the managed PriorityClass to the applied objects if any of the components uses it.
+// The PriorityClass is not removed when the components stop using it, because it is also used by keda-manager itself;
+// it is removed together with the module unless a workload outside of the module still uses it.
+func sFnUpdatePriorityClass(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	if usesDefaultPriorityClass(&s.instance) {
+		r.Objs = append([]unstructured.Unstructured{fixPriorityClassObject()}, r.Objs...)
+	}
+	return switchState(sFnApply)
+}
+
+func usesDefaultPriorityClass(k *v1alpha1.Keda) bool {
+	for _, name := range []*string{
+		priorityClassNameOperator(k),
+		priorityClassNameMetricsSvr(k),
+		priorityClassNameAdmissionWebhook(k),
+	} {
+		if *name == v1alpha1.DefaultPriorityClassName {
+			return true
+		}
+	}
+	return false
+}
+
+func fixPriorityClassObject() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "PriorityClass",
+			"apiVersion": "scheduling.k8s.io/v1",
+			"metadata": map[string]interface{}{
+				"name": v1alpha1.DefaultPriorityClassName,
+			},
+			"value":         defaultPriorityClassValue,
+			"globalDefault": false,
+			"description":   "Scheduling priority of the Keda-Manager module. Must not be blocked by unschedulable user workloads.",
+		},
+	}
+}
+
+// isPriorityClassUsedOutsideModule returns true if a Deployment which is not a module component uses the managed PriorityClass,
+// e.g. keda-manager installed from the kustomize manifests; removing the class would block scheduling of its pods
+func isPriorityClassUsedOutsideModule(ctx context.Context, r *fsm) (bool, error) {
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments); err != nil {
+		return false, err
+	}
+
+	for _, deployment := range deployments.Items {
+		if deployment.Spec.Template.Spec.PriorityClassName != v1alpha1.DefaultPriorityClassName ||
+			!deployment.GetDeletionTimestamp().IsZero() || isModuleDeployment(r.Objs, deployment) {
+			continue
+		}
+		r.log.
+			With("deployment", client.ObjectKeyFromObject(&deployment)).
+			Infof("priority class %s is still in use, skipping its deletion", v1alpha1.DefaultPriorityClassName)
+		return true, nil
+	}
+	return false, nil
+}
+
+func isModuleDeployment(objs []unstructured.Unstructured, deployment appsv1.Deployment) bool {
+	for _, obj := range objs {
+		if isDeployment(obj) && obj.GetNamespace() == deployment.GetNamespace() && obj.GetName() == deployment.GetName() {
+			return true
+		}
+	}
+	return false
+}
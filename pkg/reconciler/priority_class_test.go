@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_priorityClassName(t *testing.T) {
+	t.Run("use default priority class", func(t *testing.T) {
+		k := &v1alpha1.Keda{}
+
+		require.Equal(t, v1alpha1.DefaultPriorityClassName, *priorityClassNameOperator(k))
+		require.Equal(t, v1alpha1.DefaultPriorityClassName, *priorityClassNameMetricsSvr(k))
+		require.Equal(t, v1alpha1.DefaultPriorityClassName, *priorityClassNameAdmissionWebhook(k))
+		require.True(t, usesDefaultPriorityClass(k))
+	})
+
+	t.Run("use global and per-component priority classes", func(t *testing.T) {
+		k := &v1alpha1.Keda{
+			Spec: v1alpha1.KedaSpec{
+				PriorityClassName: "cluster-critical",
+				ComponentPriorityClassNames: &v1alpha1.PriorityClassNames{
+					MetricsServer: "api-critical",
+				},
+			},
+		}
+
+		require.Equal(t, "cluster-critical", *priorityClassNameOperator(k))
+		require.Equal(t, "api-critical", *priorityClassNameMetricsSvr(k))
+		require.Equal(t, "cluster-critical", *priorityClassNameAdmissionWebhook(k))
+		require.False(t, usesDefaultPriorityClass(k))
+	})
+}
+
+func Test_sFnUpdatePriorityClass(t *testing.T) {
+	t.Run("prepend managed priority class", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{Objs: []unstructured.Unstructured{testDeployment}},
+		}
+		s := &systemState{}
+
+		next, result, err := sFnUpdatePriorityClass(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnApply, next)
+		require.Len(t, r.Objs, 2)
+		require.Equal(t, "PriorityClass", r.Objs[0].GetKind())
+		require.Equal(t, v1alpha1.DefaultPriorityClassName, r.Objs[0].GetName())
+	})
+
+	t.Run("skip managed priority class when not used", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{Objs: []unstructured.Unstructured{testDeployment}},
+		}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{PriorityClassName: "cluster-critical"},
+			},
+		}
+
+		_, _, err := sFnUpdatePriorityClass(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Len(t, r.Objs, 1)
+	})
+}
@@ -0,0 +1,119 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// buildInventory returns the inventory entries of the given objects
+func buildInventory(objs []unstructured.Unstructured) []v1alpha1.InventoryEntry {
+	inventory := make([]v1alpha1.InventoryEntry, 0, len(objs))
+	for _, obj := range objs {
+		inventory = append(inventory, v1alpha1.InventoryEntry{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		})
+	}
+	return inventory
+}
+
+// staleInventory returns the inventory entries that are not part of the given objects anymore;
+// entries are compared by group, kind, namespace and name so that api version bumps are not pruned
+func staleInventory(inventory []v1alpha1.InventoryEntry, objs []unstructured.Unstructured) []v1alpha1.InventoryEntry {
+	current := map[v1alpha1.InventoryEntry]struct{}{}
+	for _, entry := range buildInventory(objs) {
+		current[inventoryKey(entry)] = struct{}{}
+	}
+
+	var stale []v1alpha1.InventoryEntry
+	for _, entry := range inventory {
+		if _, found := current[inventoryKey(entry)]; found || !isPrunable(entry) {
+			continue
+		}
+		stale = append(stale, entry)
+	}
+	return stale
+}
+
+func inventoryKey(entry v1alpha1.InventoryEntry) v1alpha1.InventoryEntry {
+	gv, _ := schema.ParseGroupVersion(entry.APIVersion)
+	entry.APIVersion = gv.Group
+	return entry
+}
+
+// isPrunable protects the objects whose removal would break the cluster workloads:
+// CRDs (removal deletes all KEDA custom resources) and the priority class used by keda-manager itself
+func isPrunable(entry v1alpha1.InventoryEntry) bool {
+	switch entry.Kind {
+	case "CustomResourceDefinition":
+		return false
+	case "PriorityClass":
+		return entry.Name != v1alpha1.DefaultPriorityClassName
+	}
+	return true
+}
+
+// inventoryObj returns the object identified by the inventory entry
+func inventoryObj(entry v1alpha1.InventoryEntry) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(entry.APIVersion)
+	obj.SetKind(entry.Kind)
+	obj.SetNamespace(entry.Namespace)
+	obj.SetName(entry.Name)
+	return obj
+}
+
+// getManagedObj returns the live object if it exists and is managed by keda-manager
+func getManagedObj(ctx context.Context, r *fsm, entry v1alpha1.InventoryEntry) (*unstructured.Unstructured, error) {
+	obj := inventoryObj(entry)
+	err := r.Get(ctx, client.ObjectKeyFromObject(&obj), &obj)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.GetLabels()[managedByLabel] != "keda-manager" {
+		return nil, nil
+	}
+	return &obj, nil
+}
+
+// pruneObjs deletes the managed objects from the inventory that are not part of the applied objects anymore
+func pruneObjs(ctx context.Context, r *fsm, inventory []v1alpha1.InventoryEntry) error {
+	var pruneErr error
+	for _, entry := range staleInventory(inventory, r.Objs) {
+		obj, err := getManagedObj(ctx, r, entry)
+		if err != nil {
+			pruneErr = errors.Join(pruneErr, err)
+			continue
+		}
+		if obj == nil {
+			continue
+		}
+
+		r.log.
+			With("kind", entry.Kind).
+			With("name", entry.Name).
+			With("ns", entry.Namespace).
+			Info("pruning object removed from the manifest")
+
+		err = r.Delete(ctx, obj)
+		if client.IgnoreNotFound(err) != nil {
+			pruneErr = errors.Join(pruneErr, err)
+		}
+	}
+	return pruneErr
+}
@@ -0,0 +1,79 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fixPruneTestObj(kind, name string, managed bool) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("kyma-system")
+	obj.SetName(name)
+	if managed {
+		obj.SetLabels(map[string]string{managedByLabel: "keda-manager"})
+	}
+	return obj
+}
+
+func Test_staleInventory(t *testing.T) {
+	objs := []unstructured.Unstructured{
+		fixPruneTestObj("Service", "current", true),
+	}
+	inventory := []v1alpha1.InventoryEntry{
+		{APIVersion: "v1", Kind: "Service", Namespace: "kyma-system", Name: "current"},
+		{APIVersion: "v1", Kind: "Service", Namespace: "kyma-system", Name: "removed"},
+		{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "scaledobjects.keda.sh"},
+		{APIVersion: "scheduling.k8s.io/v1", Kind: "PriorityClass", Name: v1alpha1.DefaultPriorityClassName},
+	}
+
+	require.Equal(t, []v1alpha1.InventoryEntry{
+		{APIVersion: "v1", Kind: "Service", Namespace: "kyma-system", Name: "removed"},
+	}, staleInventory(inventory, objs))
+}
+
+func Test_sFnPrune(t *testing.T) {
+	t.Run("prune managed objects removed from the manifest", func(t *testing.T) {
+		current := fixPruneTestObj("Service", "current", true)
+		removed := fixPruneTestObj("Service", "removed", true)
+		unmanaged := fixPruneTestObj("ConfigMap", "unmanaged", false)
+		c := fake.NewClientBuilder().WithObjects(&current, &removed, &unmanaged).Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{current}},
+		}
+		s := &systemState{}
+		s.instance.Status.Inventory = buildInventory([]unstructured.Unstructured{current, removed, unmanaged})
+
+		next, result, err := sFnPrune(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnVerify, next)
+
+		require.True(t, canGetFakeResource(c, current))
+		require.False(t, canGetFakeResource(c, removed))
+		require.True(t, canGetFakeResource(c, unmanaged))
+		require.Equal(t, buildInventory(r.Objs), s.instance.Status.Inventory)
+	})
+
+	t.Run("record inventory without previous one", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: fake.NewClientBuilder().Build()},
+			Cfg: Cfg{Objs: []unstructured.Unstructured{fixPruneTestObj("Service", "current", true)}},
+		}
+		s := &systemState{}
+
+		_, _, err := sFnPrune(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Len(t, s.instance.Status.Inventory, 1)
+	})
+}
@@ -3,35 +3,57 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func sFnServedFilter(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
-	if s.instance.IsServedEmpty() || s.instance.Status.Served == v1alpha1.ServedFalse {
-		// keda CRs check
-		servedKeda, err := findServedKeda(ctx, r.Client)
-		if err != nil {
-			return stopWithErrorAndNoRequeue(err)
+	var kedaList v1alpha1.KedaList
+	if err := r.Client.List(ctx, &kedaList); err != nil {
+		return stopWithErrorAndNoRequeue(err)
+	}
+
+	servedKeda := electServedKeda(withInstance(kedaList.Items, s.instance))
+	if isSameKeda(servedKeda, &s.instance) {
+		if s.instance.Status.Served == v1alpha1.ServedTrue {
+			return switchState(sFnTakeSnapshot)
 		}
 
 		s.instance.UpdateServed(v1alpha1.ServedTrue)
-		if servedKeda != nil {
-			s.instance.UpdateServed(v1alpha1.ServedFalse)
-			s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeInstalled, v1alpha1.ConditionReasonKedaDuplicated,
-				fmt.Errorf("only one instance of Keda is allowed (current served instance: %s/%s)",
-					servedKeda.GetNamespace(), servedKeda.GetName()))
+		return stopWithRequeue()
+	}
+
+	// the finalizer of a demoted instance must not trigger the deletion of the resources,
+	// they are owned by the served instance
+	if !s.instance.GetDeletionTimestamp().IsZero() {
+		if controllerutil.ContainsFinalizer(&s.instance, r.Finalizer) {
+			return switchState(sFnRemoveFinalizer)
 		}
+		return stopWithNoRequeue()
+	}
 
+	if s.instance.Status.Served == v1alpha1.ServedTrue {
+		r.log.Warnf("demoting served instance, %s/%s is served as well", servedKeda.GetNamespace(), servedKeda.GetName())
+		s.instance.UpdateServed(v1alpha1.ServedFalse)
+		s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeInstalled, v1alpha1.ConditionReasonServedConflict,
+			fmt.Errorf("instance demoted, %s/%s is served as well and takes precedence: %s",
+				servedKeda.GetNamespace(), servedKeda.GetName(), precedenceReason(servedKeda, &s.instance)))
 		return stopWithRequeue()
 	}
 
-	return switchState(sFnTakeSnapshot)
+	s.instance.UpdateServed(v1alpha1.ServedFalse)
+	s.instance.UpdateStateFromErr(v1alpha1.ConditionTypeInstalled, v1alpha1.ConditionReasonKedaDuplicated,
+		fmt.Errorf("only one instance of Keda is allowed (current served instance: %s/%s)",
+			servedKeda.GetNamespace(), servedKeda.GetName()))
+	return stopWithRequeue()
 }
 
-func findServedKeda(ctx context.Context, c client.Client) (*v1alpha1.Keda, error) {
+// FindServedKeda returns the Keda CR served by keda-manager, nil if there is none
+func FindServedKeda(ctx context.Context, c client.Reader) (*v1alpha1.Keda, error) {
 	var kedaList v1alpha1.KedaList
 
 	err := c.List(ctx, &kedaList)
@@ -40,11 +62,71 @@ func findServedKeda(ctx context.Context, c client.Client) (*v1alpha1.Keda, error
 		return nil, err
 	}
 
-	for _, item := range kedaList.Items {
-		if !item.IsServedEmpty() && item.Status.Served == v1alpha1.ServedTrue {
-			return &item, nil
+	servedKeda := electServedKeda(kedaList.Items)
+	if servedKeda == nil || servedKeda.Status.Served != v1alpha1.ServedTrue {
+		return nil, nil
+	}
+
+	return servedKeda, nil
+}
+
+// electServedKeda returns the instance which should be served, the election is deterministic
+// so every reconciliation picks the same instance regardless of the list order:
+// - the served instance is kept, if more than one instance is served (e.g. after concurrent creation)
+// the one taking precedence stays served and the others are demoted
+// - otherwise the instance taking precedence among the ones not being deleted is elected
+func electServedKeda(items []v1alpha1.Keda) *v1alpha1.Keda {
+	var served, candidate *v1alpha1.Keda
+	for i := range items {
+		item := &items[i]
+		if item.Status.Served == v1alpha1.ServedTrue && (served == nil || takesPrecedence(item, served)) {
+			served = item
+		}
+		if item.GetDeletionTimestamp().IsZero() && (candidate == nil || takesPrecedence(item, candidate)) {
+			candidate = item
+		}
+	}
+
+	if served != nil {
+		return served
+	}
+	return candidate
+}
+
+// takesPrecedence returns true if the first instance is older than the second one,
+// instances created at the same time are ordered by namespace and name
+func takesPrecedence(k1, k2 *v1alpha1.Keda) bool {
+	t1, t2 := k1.GetCreationTimestamp(), k2.GetCreationTimestamp()
+	if !t1.Equal(&t2) {
+		return t1.Before(&t2)
+	}
+	if k1.GetNamespace() != k2.GetNamespace() {
+		return k1.GetNamespace() < k2.GetNamespace()
+	}
+	return k1.GetName() < k2.GetName()
+}
+
+func precedenceReason(winner, loser *v1alpha1.Keda) string {
+	t1, t2 := winner.GetCreationTimestamp(), loser.GetCreationTimestamp()
+	if !t1.Equal(&t2) {
+		return fmt.Sprintf("it was created earlier (%s)", t1.UTC().Format(time.RFC3339))
+	}
+	return "it was created at the same time and precedes by namespace and name"
+}
+
+// withInstance replaces the listed copy of the reconciled instance with its current state,
+// the list comes from the cache and may not contain the instance yet
+func withInstance(items []v1alpha1.Keda, instance v1alpha1.Keda) []v1alpha1.Keda {
+	for i := range items {
+		if isSameKeda(&items[i], &instance) {
+			items[i] = instance
+			return items
 		}
 	}
+	return append(items, instance)
+}
 
-	return nil, nil
+func isSameKeda(k1, k2 *v1alpha1.Keda) bool {
+	return k1 != nil && k2 != nil &&
+		k1.GetNamespace() == k2.GetNamespace() && k1.GetName() == k2.GetName()
 }
@@ -2,16 +2,19 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/onsi/gomega"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -53,7 +56,13 @@ func Test_sFnServedFilter(t *testing.T) {
 			},
 		}
 
-		nextFn, result, err := sFnServedFilter(context.TODO(), nil, s)
+		r := &fsm{
+			K8s: K8s{
+				Client: fixClient(t),
+			},
+		}
+
+		nextFn, result, err := sFnServedFilter(context.TODO(), r, s)
 
 		require.Nil(t, err)
 		requireEqualFunc(t, sFnTakeSnapshot, nextFn)
@@ -128,6 +137,213 @@ func Test_sFnServedFilter(t *testing.T) {
 	})
 }
 
+func Test_sFnServedFilter_election(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	older := now.Add(-time.Hour)
+
+	t.Run("elect the oldest instance", func(t *testing.T) {
+		s := &systemState{
+			instance: *fixKedaCreatedAt("test-1", "default", "", now),
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					fixKedaCreatedAt("test-1", "default", "", now),
+					fixKedaCreatedAt("test-2", "keda-test", "", older),
+				),
+			},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnUpdateStatus(&ctrl.Result{Requeue: true}, nil), nextFn)
+		require.Equal(t, v1alpha1.ServedFalse, s.instance.Status.Served)
+		requireCondition(t, s.instance, v1alpha1.ConditionReasonKedaDuplicated,
+			"only one instance of Keda is allowed (current served instance: keda-test/test-2)")
+	})
+
+	t.Run("elect by namespace and name when created at the same time", func(t *testing.T) {
+		s := &systemState{
+			instance: *fixKedaCreatedAt("test-1", "default", "", now),
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					fixKedaCreatedAt("test-2", "default", "", now),
+					fixKedaCreatedAt("test-1", "keda-test", "", now),
+				),
+			},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnUpdateStatus(&ctrl.Result{Requeue: true}, nil), nextFn)
+		require.Equal(t, v1alpha1.ServedTrue, s.instance.Status.Served)
+	})
+
+	t.Run("skip instances being deleted", func(t *testing.T) {
+		deleted := fixKedaCreatedAt("test-2", "default", "", older)
+		deleted.DeletionTimestamp = &metav1.Time{Time: now}
+		deleted.Finalizers = []string{"test"}
+		s := &systemState{
+			instance: *fixKedaCreatedAt("test-1", "default", "", now),
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t, deleted),
+			},
+		}
+
+		_, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		require.Equal(t, v1alpha1.ServedTrue, s.instance.Status.Served)
+	})
+
+	t.Run("keep serving the instance being deleted", func(t *testing.T) {
+		deleted := fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, now)
+		deleted.DeletionTimestamp = &metav1.Time{Time: now}
+		deleted.Finalizers = []string{"test"}
+		s := &systemState{
+			instance: *deleted,
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					deleted,
+					fixKedaCreatedAt("test-2", "default", v1alpha1.ServedFalse, older),
+				),
+			},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnTakeSnapshot, nextFn)
+	})
+
+	t.Run("demote the served instance on conflict", func(t *testing.T) {
+		s := &systemState{
+			instance: *fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, now),
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, now),
+					fixKedaCreatedAt("test-2", "keda-test", v1alpha1.ServedTrue, older),
+				),
+			},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnUpdateStatus(&ctrl.Result{Requeue: true}, nil), nextFn)
+		require.Equal(t, v1alpha1.ServedFalse, s.instance.Status.Served)
+		require.Equal(t, v1alpha1.StateError, s.instance.Status.State)
+		requireCondition(t, s.instance, v1alpha1.ConditionReasonServedConflict,
+			fmt.Sprintf("instance demoted, keda-test/test-2 is served as well and takes precedence: it was created earlier (%s)",
+				older.UTC().Format(time.RFC3339)))
+	})
+
+	t.Run("keep the oldest served instance on conflict", func(t *testing.T) {
+		s := &systemState{
+			instance: *fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, older),
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, older),
+					fixKedaCreatedAt("test-2", "keda-test", v1alpha1.ServedTrue, now),
+				),
+			},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnTakeSnapshot, nextFn)
+	})
+
+	t.Run("remove finalizer of the demoted instance being deleted", func(t *testing.T) {
+		deleted := fixKedaCreatedAt("test-1", "default", v1alpha1.ServedFalse, now)
+		deleted.DeletionTimestamp = &metav1.Time{Time: now}
+		deleted.Finalizers = []string{"test"}
+		s := &systemState{
+			instance: *deleted,
+		}
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{
+				Client: fixClient(t,
+					deleted,
+					fixKedaCreatedAt("test-2", "keda-test", v1alpha1.ServedTrue, older),
+				),
+			},
+			Cfg: Cfg{Finalizer: "test"},
+		}
+
+		nextFn, _, err := sFnServedFilter(context.TODO(), r, s)
+
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnRemoveFinalizer, nextFn)
+	})
+}
+
+func TestFindServedKeda(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("no served instance", func(t *testing.T) {
+		c := fixClient(t, fixKedaCreatedAt("test-1", "default", v1alpha1.ServedFalse, now))
+
+		servedKeda, err := FindServedKeda(context.TODO(), c)
+
+		require.NoError(t, err)
+		require.Nil(t, servedKeda)
+	})
+
+	t.Run("oldest served instance", func(t *testing.T) {
+		c := fixClient(t,
+			fixKedaCreatedAt("test-1", "default", v1alpha1.ServedTrue, now),
+			fixKedaCreatedAt("test-2", "default", v1alpha1.ServedTrue, now.Add(-time.Minute)),
+			fixKedaCreatedAt("test-3", "default", v1alpha1.ServedFalse, now.Add(-time.Hour)),
+		)
+
+		servedKeda, err := FindServedKeda(context.TODO(), c)
+
+		require.NoError(t, err)
+		require.Equal(t, "test-2", servedKeda.GetName())
+	})
+}
+
+func requireCondition(t *testing.T, keda v1alpha1.Keda, reason v1alpha1.ConditionReason, message string) {
+	expectedCondition := metav1.Condition{
+		Type:    string(v1alpha1.ConditionTypeInstalled),
+		Status:  "False",
+		Reason:  string(reason),
+		Message: message,
+	}
+	opt := cmp.Comparer(func(x, y metav1.Condition) bool {
+		return x.Type == y.Type && x.Status == y.Status && x.Reason == y.Reason && x.Message == y.Message
+	})
+	g := gomega.NewWithT(t)
+	g.Expect(keda.Status.Conditions).Should(gomega.ContainElement(gomega.BeComparableTo(expectedCondition, opt)))
+}
+
+func fixKedaCreatedAt(name, namespace string, served string, created time.Time) *v1alpha1.Keda {
+	keda := fixServedKeda(name, namespace, served)
+	keda.CreationTimestamp = metav1.Time{Time: created}
+	return keda
+}
+
 func fixServedKeda(name, namespace string, served string) *v1alpha1.Keda {
 	return &v1alpha1.Keda{
 		ObjectMeta: metav1.ObjectMeta{
@@ -8,5 +8,5 @@ import (
 
 func sFnTakeSnapshot(_ context.Context, _ *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
 	s.saveKedaStatus()
-	return sFnInitialize, nil, nil
+	return sFnPausedFilter, nil, nil
 }
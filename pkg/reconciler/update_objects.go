@@ -86,7 +86,7 @@ func buildSfnUpdateOperatorAnnotations(u *unstructured.Unstructured) stateFn {
 
 func buildSfnUpdateOperatorPriorityClass(u *unstructured.Unstructured) stateFn {
 	next := buildSfnUpdateOperatorResources(u)
-	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassName, next)
+	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassNameOperator, next)
 }
 
 func buildSfnUpdateOperatorResources(u *unstructured.Unstructured) stateFn {
@@ -95,7 +95,17 @@ func buildSfnUpdateOperatorResources(u *unstructured.Unstructured) stateFn {
 }
 
 func buildSfnUpdateOperatorEnvs(u *unstructured.Unstructured) stateFn {
-	return buildSfnUpdateObject(u, updateKedaContanierEnvs, envVars, sFnUpdateMetricsServerDeployment)
+	next := buildSfnUpdateOperatorReplicas(u)
+	return buildSfnUpdateObject(u, updateKedaContanierEnvs, envVars, next)
+}
+
+func buildSfnUpdateOperatorReplicas(u *unstructured.Unstructured) stateFn {
+	next := buildSfnUpdateOperatorScheduling(u)
+	return buildSfnUpdateObject(u, updateDeploymentReplicas, operatorReplicas, next)
+}
+
+func buildSfnUpdateOperatorScheduling(u *unstructured.Unstructured) stateFn {
+	return buildSfnUpdateObject(u, updateDeploymentScheduling, schedulingOperatorCfg, sFnUpdateMetricsServerDeployment)
 }
 
 func sFnUpdateMetricsServerDeployment(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
@@ -129,7 +139,7 @@ func buildSfnUpdateMetricsSvrAnnotations(u *unstructured.Unstructured) stateFn {
 
 func buildSfnUpdateMetricsSvrPriorityClass(u *unstructured.Unstructured) stateFn {
 	next := buildSfnUpdateMetricsSvrResources(u)
-	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassName, next)
+	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassNameMetricsSvr, next)
 }
 
 func buildSfnUpdateMetricsSvrResources(u *unstructured.Unstructured) stateFn {
@@ -138,7 +148,17 @@ func buildSfnUpdateMetricsSvrResources(u *unstructured.Unstructured) stateFn {
 }
 
 func buildSfnUpdateMetricsSvrEnvVars(u *unstructured.Unstructured) stateFn {
-	return buildSfnUpdateObject(u, updateKedaContanierEnvs, envVars, sFnUpdateAdmissionWebhooksDeployment)
+	next := buildSfnUpdateMetricsSvrReplicas(u)
+	return buildSfnUpdateObject(u, updateKedaContanierEnvs, envVars, next)
+}
+
+func buildSfnUpdateMetricsSvrReplicas(u *unstructured.Unstructured) stateFn {
+	next := buildSfnUpdateMetricsSvrScheduling(u)
+	return buildSfnUpdateObject(u, updateDeploymentReplicas, metricsSvrReplicas, next)
+}
+
+func buildSfnUpdateMetricsSvrScheduling(u *unstructured.Unstructured) stateFn {
+	return buildSfnUpdateObject(u, updateDeploymentScheduling, schedulingMetricsSvrCfg, sFnUpdateAdmissionWebhooksDeployment)
 }
 
 func sFnUpdateAdmissionWebhooksDeployment(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
@@ -176,7 +196,17 @@ func buildSfnUpdateAdmissionWebhooksResources(u *unstructured.Unstructured) stat
 }
 
 func buildSfnUpdateAdmissionWebhooksPriorityClass(u *unstructured.Unstructured) stateFn {
-	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassName, sfnUpdateAdmissionWebhooksNetworkPolicy)
+	next := buildSfnUpdateAdmissionWebhooksReplicas(u)
+	return buildSfnUpdateObject(u, updateDeploymentPriorityClass, priorityClassNameAdmissionWebhook, next)
+}
+
+func buildSfnUpdateAdmissionWebhooksReplicas(u *unstructured.Unstructured) stateFn {
+	next := buildSfnUpdateAdmissionWebhooksScheduling(u)
+	return buildSfnUpdateObject(u, updateDeploymentReplicas, admissionWebhookReplicas, next)
+}
+
+func buildSfnUpdateAdmissionWebhooksScheduling(u *unstructured.Unstructured) stateFn {
+	return buildSfnUpdateObject(u, updateDeploymentScheduling, schedulingAdmissionWebhookCfg, sfnUpdateAdmissionWebhooksNetworkPolicy)
 }
 
 func sfnUpdateAdmissionWebhooksNetworkPolicy(ctx context.Context, f *fsm, ss *systemState) (stateFn, *ctrl.Result, error) {
@@ -193,7 +223,7 @@ func sfnUpdateAdmissionWebhooksNetworkPolicy(ctx context.Context, f *fsm, ss *sy
 	ipBlock := fmt.Sprintf("%s/32", f.APIServerIP)
 
 	return switchState(
-		buildSfnUpdateObject(np, updateAdmissionWebhooksNetworkPolicy, networkPolicyAPIServerAddress(ipBlock), sFnApply),
+		buildSfnUpdateObject(np, updateAdmissionWebhooksNetworkPolicy, networkPolicyAPIServerAddress(ipBlock), sFnUpdatePodDisruptionBudgets),
 	)
 }
 
@@ -253,10 +283,14 @@ func metricsSvrResources(k *v1alpha1.Keda) *corev1.ResourceRequirements {
 }
 
 func envVars(k *v1alpha1.Keda) *v1alpha1.EnvVars {
-	if k != nil {
+	if k == nil {
+		return nil
+	}
+	if len(k.Spec.WatchNamespaces) == 0 {
 		return &k.Spec.Env
 	}
-	return nil
+	envs := k.Spec.Env.WithWatchNamespace(k.Spec.WatchNamespace())
+	return &envs
 }
 
 func networkPolicyAPIServerAddress(address string) func(*v1alpha1.Keda) *string {
@@ -271,9 +305,41 @@ func disabledIstioSidecar(_ *v1alpha1.Keda) *v1alpha1.IstioCfg {
 	}
 }
 
-func priorityClassName(_ *v1alpha1.Keda) *string {
-	priorityClassName := "keda-priority-class"
-	return &priorityClassName
+// priorityClassName returns component priority class name, falls back to the global one and the default
+func priorityClassName(k *v1alpha1.Keda, component func(*v1alpha1.PriorityClassNames) v1alpha1.PriorityClassName) string {
+	if k == nil {
+		return v1alpha1.DefaultPriorityClassName
+	}
+	if k.Spec.ComponentPriorityClassNames != nil {
+		if name := component(k.Spec.ComponentPriorityClassNames); name != "" {
+			return string(name)
+		}
+	}
+	if k.Spec.PriorityClassName != "" {
+		return string(k.Spec.PriorityClassName)
+	}
+	return v1alpha1.DefaultPriorityClassName
+}
+
+func priorityClassNameOperator(k *v1alpha1.Keda) *string {
+	name := priorityClassName(k, func(n *v1alpha1.PriorityClassNames) v1alpha1.PriorityClassName {
+		return n.Operator
+	})
+	return &name
+}
+
+func priorityClassNameMetricsSvr(k *v1alpha1.Keda) *string {
+	name := priorityClassName(k, func(n *v1alpha1.PriorityClassNames) v1alpha1.PriorityClassName {
+		return n.MetricsServer
+	})
+	return &name
+}
+
+func priorityClassNameAdmissionWebhook(k *v1alpha1.Keda) *string {
+	name := priorityClassName(k, func(n *v1alpha1.PriorityClassNames) v1alpha1.PriorityClassName {
+		return n.AdmissionWebhook
+	})
+	return &name
 }
 
 func admissionWebhookResources(k *v1alpha1.Keda) *corev1.ResourceRequirements {
@@ -282,3 +348,45 @@ func admissionWebhookResources(k *v1alpha1.Keda) *corev1.ResourceRequirements {
 	}
 	return nil
 }
+
+func operatorReplicas(k *v1alpha1.Keda) *int32 {
+	if k != nil && k.Spec.Replicas != nil {
+		return k.Spec.Replicas.Operator
+	}
+	return nil
+}
+
+func metricsSvrReplicas(k *v1alpha1.Keda) *int32 {
+	if k != nil && k.Spec.Replicas != nil {
+		return k.Spec.Replicas.MetricsServer
+	}
+	return nil
+}
+
+func admissionWebhookReplicas(k *v1alpha1.Keda) *int32 {
+	if k != nil && k.Spec.Replicas != nil {
+		return k.Spec.Replicas.AdmissionWebhook
+	}
+	return nil
+}
+
+func schedulingOperatorCfg(k *v1alpha1.Keda) *v1alpha1.SchedulingCfg {
+	if k != nil && k.Spec.Scheduling != nil {
+		return k.Spec.Scheduling.Operator
+	}
+	return nil
+}
+
+func schedulingMetricsSvrCfg(k *v1alpha1.Keda) *v1alpha1.SchedulingCfg {
+	if k != nil && k.Spec.Scheduling != nil {
+		return k.Spec.Scheduling.MetricsServer
+	}
+	return nil
+}
+
+func schedulingAdmissionWebhookCfg(k *v1alpha1.Keda) *v1alpha1.SchedulingCfg {
+	if k != nil && k.Spec.Scheduling != nil {
+		return k.Spec.Scheduling.AdmissionWebhook
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 func sFnUpdateStatus(result *ctrl.Result, err error) stateFn {
 	return func(ctx context.Context, m *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+		s.instance.Status.ObservedGeneration = s.instance.GetGeneration()
 		updateErr := m.Status().Update(ctx, &s.instance)
 		if updateErr != nil {
 			m.log.With("updateErr", updateErr).Warn("unable to update instance status")
@@ -0,0 +1,148 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// addonUpgradeTimeout is the time the new add-on installation has to become ready before the previous one is restored
+const addonUpgradeTimeout = 5 * time.Minute
+
+// trackAddonUpgrade returns the upgrade in progress when the add-on version or namespace differs from the installed one
+func trackAddonUpgrade(installed *v1alpha1.AddonStatus, version, namespace string) *v1alpha1.AddonUpgradeStatus {
+	upgrade := installed.Upgrade
+	if upgrade != nil {
+		if upgrade.ToVersion == version && upgrade.ToNamespace == namespace {
+			return upgrade
+		}
+		// the configuration changed during the upgrade, keep the objects of all involved installations to prune them later
+		upgrade.ToVersion, upgrade.ToNamespace = version, namespace
+		upgrade.StartedAt = metav1.Now()
+		upgrade.RolledBack = false
+		upgrade.PreviousInventory = mergeInventory(upgrade.PreviousInventory, installed.Inventory)
+		return upgrade
+	}
+
+	installedNS := installed.Namespace
+	if installedNS == "" {
+		installedNS = namespace
+	}
+	if installed.Version == "" || (installed.Version == version && installedNS == namespace) {
+		return nil
+	}
+
+	installed.Upgrade = &v1alpha1.AddonUpgradeStatus{
+		FromVersion:       installed.Version,
+		FromNamespace:     installedNS,
+		ToVersion:         version,
+		ToNamespace:       namespace,
+		StartedAt:         metav1.Now(),
+		PreviousInventory: installed.Inventory,
+		FromSource:        installed.Source,
+	}
+	return installed.Upgrade
+}
+
+func mergeInventory(inventory, entries []v1alpha1.InventoryEntry) []v1alpha1.InventoryEntry {
+	known := map[v1alpha1.InventoryEntry]struct{}{}
+	for _, entry := range inventory {
+		known[inventoryKey(entry)] = struct{}{}
+	}
+	for _, entry := range entries {
+		if _, found := known[inventoryKey(entry)]; found {
+			continue
+		}
+		known[inventoryKey(entry)] = struct{}{}
+		inventory = append(inventory, entry)
+	}
+	return inventory
+}
+
+// previousAddonObjs returns the objects of the installation replaced by the upgrade; the manifest is
+// fetched only for add-ons installed without the inventory
+func previousAddonObjs(ctx context.Context, r *fsm, s *systemState, a Addon, upgrade *v1alpha1.AddonUpgradeStatus) ([]unstructured.Unstructured, error) {
+	if len(upgrade.PreviousInventory) > 0 {
+		return inventoryObjs(upgrade.PreviousInventory), nil
+	}
+	src := addonSourceFromStatus(&s.instance, upgrade.FromSource)
+	src.toDelete = true
+	return fetchAddonObjs(ctx, r, s, a, src, upgrade.FromVersion, upgrade.FromNamespace)
+}
+
+// finishAddonUpgrade removes the objects of the previous installation that are not part of the verified one
+func finishAddonUpgrade(ctx context.Context, r *fsm, s *systemState, a Addon, installed *v1alpha1.AddonStatus) error {
+	upgrade := installed.Upgrade
+	if upgrade == nil {
+		return nil
+	}
+
+	previous, err := previousAddonObjs(ctx, r, s, a, upgrade)
+	if err != nil {
+		return fmt.Errorf("failed to get the objects of the previous %s installation: %w", a.Title(), err)
+	}
+	r.log.Infof("%s upgraded from v%s in %s to v%s in %s, removing old resources",
+		a.Title(), upgrade.FromVersion, upgrade.FromNamespace, upgrade.ToVersion, upgrade.ToNamespace)
+	if err := deleteObjects(ctx, r, inventoryObjs(staleInventory(buildInventory(previous), r.AddonObjs[a.Name()]))); err != nil {
+		return err
+	}
+	if upgrade.FromNamespace != upgrade.ToNamespace {
+		if err := deleteOptionalAddonResources(ctx, r, a, upgrade.FromNamespace, nil); err != nil {
+			return err
+		}
+	}
+
+	installed.Upgrade = nil
+	s.instance.SetAddonStatus(*installed)
+	return nil
+}
+
+// rollbackAddon restores the previous installation when the upgraded add-on was not ready within the timeout
+func rollbackAddon(ctx context.Context, r *fsm, s *systemState, a Addon, installed v1alpha1.AddonStatus, notReady []string) (stateFn, *ctrl.Result, error) {
+	upgrade := installed.Upgrade
+	reason := fmt.Sprintf("%s v%s was not ready within %s, waiting for: %s",
+		a.Title(), upgrade.ToVersion, addonUpgradeTimeout, strings.Join(notReady, ", "))
+
+	r.log.Infof("%s, rolling back to v%s", reason, upgrade.FromVersion)
+	src := addonSourceFromStatus(&s.instance, upgrade.FromSource)
+	objs, err := fetchAddonObjs(ctx, r, s, a, src, upgrade.FromVersion, upgrade.FromNamespace)
+	if err == nil {
+		err = applyObjects(ctx, r, objs)
+	}
+	if err != nil {
+		r.log.With("err", err).Errorf("failed to roll back %s", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonUpgradeFailed,
+			fmt.Sprintf("%s; rollback to v%s failed: %s", reason, upgrade.FromVersion, err))
+		return stopAddonWithRequeueAfter(s, time.Second*10)
+	}
+
+	// remove the objects introduced by the failed installation
+	_ = deleteObjects(ctx, r, inventoryObjs(staleInventory(buildInventory(r.AddonObjs[a.Name()]), objs)))
+
+	upgrade.RolledBack = true
+	persistAddonInstallation(ctx, r, s, a, installed, objs, src, upgrade.FromVersion, upgrade.FromNamespace)
+	setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonUpgradeFailed,
+		fmt.Sprintf("%s; rolled back to v%s, change the add-on configuration to retry", reason, upgrade.FromVersion))
+	return stopAddon()
+}
+
+// persistAddonInstallation records the applied add-on objects together with the installed version, namespace and source in the status
+func persistAddonInstallation(ctx context.Context, r *fsm, s *systemState, a Addon, installed v1alpha1.AddonStatus,
+	objs []unstructured.Unstructured, src addonSource, version, namespace string) {
+	if r.AddonObjs == nil {
+		r.AddonObjs = map[string][]unstructured.Unstructured{}
+	}
+	r.AddonObjs[a.Name()] = objs
+
+	installed.Version = version
+	installed.Namespace = namespace
+	installed.Inventory = buildInventory(objs)
+	installed.Source = src.status()
+	recordAddonStatus(ctx, r, s, a, &installed)
+}
@@ -0,0 +1,239 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/addon"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func fixAddonService(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Service")
+	obj.SetNamespace("kyma-system")
+	obj.SetName(name)
+	return obj
+}
+
+func Test_trackAddonUpgrade(t *testing.T) {
+	inventory := buildInventory([]unstructured.Unstructured{fixAddonService("keda-add-ons-http-interceptor-admin")})
+
+	t.Run("not installed", func(t *testing.T) {
+		installed := &v1alpha1.AddonStatus{Name: httpAddonName}
+		require.Nil(t, trackAddonUpgrade(installed, "0.15.0", "kyma-system"))
+	})
+	t.Run("installed version", func(t *testing.T) {
+		installed := &v1alpha1.AddonStatus{Name: httpAddonName, Version: "0.15.0", Namespace: "kyma-system"}
+		require.Nil(t, trackAddonUpgrade(installed, "0.15.0", "kyma-system"))
+	})
+	t.Run("version changed", func(t *testing.T) {
+		installed := &v1alpha1.AddonStatus{Name: httpAddonName, Version: "0.14.0", Namespace: "kyma-system", Inventory: inventory}
+
+		upgrade := trackAddonUpgrade(installed, "0.15.0", "kyma-system")
+		require.NotNil(t, upgrade)
+		require.Equal(t, "0.14.0", upgrade.FromVersion)
+		require.Equal(t, "0.15.0", upgrade.ToVersion)
+		require.Equal(t, inventory, upgrade.PreviousInventory)
+		require.Equal(t, upgrade, installed.Upgrade)
+	})
+	t.Run("source of the previous installation", func(t *testing.T) {
+		source := &v1alpha1.AddonSourceStatus{ManifestsConfigMap: "http-addon-manifests"}
+		installed := &v1alpha1.AddonStatus{Name: httpAddonName, Version: "0.14.0", Namespace: "kyma-system", Source: source}
+
+		upgrade := trackAddonUpgrade(installed, "0.15.0", "kyma-system")
+		require.Equal(t, source, upgrade.FromSource)
+	})
+	t.Run("configuration changed after rollback", func(t *testing.T) {
+		newInventory := buildInventory([]unstructured.Unstructured{fixAddonService("keda-add-ons-http-interceptor-proxy")})
+		installed := &v1alpha1.AddonStatus{
+			Name:      httpAddonName,
+			Version:   "0.14.0",
+			Namespace: "kyma-system",
+			Inventory: newInventory,
+			Upgrade: &v1alpha1.AddonUpgradeStatus{
+				FromVersion:       "0.14.0",
+				FromNamespace:     "kyma-system",
+				ToVersion:         "0.15.0",
+				ToNamespace:       "kyma-system",
+				RolledBack:        true,
+				PreviousInventory: inventory,
+			},
+		}
+
+		upgrade := trackAddonUpgrade(installed, "0.16.0", "kyma-system")
+		require.Equal(t, "0.14.0", upgrade.FromVersion)
+		require.Equal(t, "0.16.0", upgrade.ToVersion)
+		require.False(t, upgrade.RolledBack)
+		require.Equal(t, append(inventory, newInventory...), upgrade.PreviousInventory)
+	})
+}
+
+func Test_sFnVerifyAddon_upgrade(t *testing.T) {
+	oldService := fixAddonService("keda-add-ons-http-old")
+	sharedService := fixAddonService("keda-add-ons-http-interceptor-admin")
+
+	t.Run("remove old objects after the new version is verified", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(oldService.DeepCopy(), sharedService.DeepCopy()).Build()
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {
+				sharedService,
+				fixVerifyDeployment(t, "keda-add-ons-http-interceptor", true),
+			}}},
+		}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{
+			Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{{
+				Name:      httpAddonName,
+				Version:   "0.15.0",
+				Namespace: "kyma-system",
+				Upgrade: &v1alpha1.AddonUpgradeStatus{
+					FromVersion:       "0.14.0",
+					FromNamespace:     "kyma-system",
+					ToVersion:         "0.15.0",
+					ToNamespace:       "kyma-system",
+					StartedAt:         metav1.Now(),
+					PreviousInventory: buildInventory([]unstructured.Unstructured{oldService, sharedService}),
+				},
+			}}},
+		}}
+
+		_, _, err := sFnVerifyAddon(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, s.instance.GetAddonStatus(httpAddonName).Upgrade)
+		require.False(t, canGetFakeResource(c, oldService))
+		require.True(t, canGetFakeResource(c, sharedService))
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.Equal(t, metav1.ConditionTrue, condition.Status)
+	})
+
+	t.Run("roll back when the new version is not ready in time", func(t *testing.T) {
+		// the add-on network policies are loaded relative to the repository root
+		t.Chdir("../..")
+
+		newService := fixAddonService("keda-add-ons-http-new")
+		var applied []string
+		// the fake client does not support server-side apply of unstructured objects
+		c := fake.NewClientBuilder().WithObjects(newService.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				applied = append(applied, obj.GetName())
+				return nil
+			},
+		}).Build()
+
+		cache := addon.NewCache()
+		cache.Set(httpAddonName+"/0.14.0//", []unstructured.Unstructured{oldService})
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{
+				AddonCache: cache,
+				AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {
+					newService,
+					fixVerifyDeployment(t, "keda-add-ons-http-interceptor", false),
+				}},
+			},
+		}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{
+			Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{{
+				Name:      httpAddonName,
+				Version:   "0.15.0",
+				Namespace: "kyma-system",
+				Upgrade: &v1alpha1.AddonUpgradeStatus{
+					FromVersion:   "0.14.0",
+					FromNamespace: "kyma-system",
+					ToVersion:     "0.15.0",
+					ToNamespace:   "kyma-system",
+					StartedAt:     metav1.NewTime(time.Now().Add(-2 * addonUpgradeTimeout)),
+				},
+			}}},
+		}}
+
+		_, _, err := sFnVerifyAddon(context.Background(), r, s)
+		require.NoError(t, err)
+		installed := s.instance.GetAddonStatus(httpAddonName)
+		require.True(t, installed.Upgrade.RolledBack)
+		require.Equal(t, "0.14.0", installed.Version)
+		require.Contains(t, applied, oldService.GetName())
+		require.False(t, canGetFakeResource(c, newService))
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.Equal(t, metav1.ConditionFalse, condition.Status)
+		require.Equal(t, v1alpha1.ConditionReasonAddonUpgradeFailed, condition.Reason)
+		require.Contains(t, condition.Message, "rolled back to v0.14.0")
+	})
+	t.Run("roll back from the ConfigMap of the previous installation", func(t *testing.T) {
+		// the add-on network policies are loaded relative to the repository root
+		t.Chdir("../..")
+
+		newService := fixAddonService("keda-add-ons-http-new")
+		var applied []string
+		c := fake.NewClientBuilder().WithObjects(newService.DeepCopy(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kyma-system", Name: "http-addon-0.14.0"},
+			Data: map[string]string{
+				"addon.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: keda-add-ons-http-old\n",
+			},
+		}).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				applied = append(applied, obj.GetName())
+				return nil
+			},
+		}).Build()
+
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: c},
+			Cfg: Cfg{
+				// the previous version is neither bundled nor cached, it must be read from the ConfigMap
+				AddonCache: addon.NewCache(),
+				AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {
+					newService,
+					fixVerifyDeployment(t, "keda-add-ons-http-interceptor", false),
+				}},
+			},
+		}
+		source := &v1alpha1.AddonSourceStatus{ManifestsConfigMap: "http-addon-0.14.0"}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kyma-system", Name: "default"},
+			Status: v1alpha1.Status{Addons: []v1alpha1.AddonStatus{{
+				Name:      httpAddonName,
+				Version:   "0.15.0",
+				Namespace: "kyma-system",
+				Upgrade: &v1alpha1.AddonUpgradeStatus{
+					FromVersion:   "0.14.0",
+					FromNamespace: "kyma-system",
+					ToVersion:     "0.15.0",
+					ToNamespace:   "kyma-system",
+					StartedAt:     metav1.NewTime(time.Now().Add(-2 * addonUpgradeTimeout)),
+					FromSource:    source,
+				},
+			}}},
+		}}
+
+		_, _, err := sFnVerifyAddon(context.Background(), r, s)
+		require.NoError(t, err)
+		installed := s.instance.GetAddonStatus(httpAddonName)
+		require.True(t, installed.Upgrade.RolledBack)
+		require.Equal(t, "0.14.0", installed.Version)
+		require.Equal(t, source, installed.Source)
+		require.Contains(t, applied, "keda-add-ons-http-old")
+		require.False(t, canGetFakeResource(c, newService))
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.Equal(t, v1alpha1.ConditionReasonAddonUpgradeFailed, condition.Reason)
+		require.Contains(t, condition.Message, "rolled back to v0.14.0")
+	})
+}
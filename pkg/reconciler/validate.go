@@ -9,7 +9,7 @@ import (
 
 func sFnBootstrapperValidation(_ context.Context, _ *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
 
-	if hasRestrictedAnnotations(s.instance) {
+	if HasRestrictedAnnotations(s.instance) {
 		err := fmt.Errorf("used restricted annotations in Keda CR %s", s.instance.GetName())
 		s.instance.UpdateStateFromErr(
 			v1alpha1.ConditionTypeInstalled,
@@ -22,7 +22,8 @@ func sFnBootstrapperValidation(_ context.Context, _ *fsm, s *systemState) (state
 	return switchState(sFnUpdateKedaDeployment)
 }
 
-func hasRestrictedAnnotations(dep v1alpha1.Keda) bool {
+// HasRestrictedAnnotations returns true if the pod annotations reserved for the Kyma bootstrapper are used in the Keda CR
+func HasRestrictedAnnotations(dep v1alpha1.Keda) bool {
 	// PodAnnotations is a pointer in the spec; guard against nil to avoid panic
 	if dep.Spec.PodAnnotations == nil {
 		return false
@@ -65,8 +65,8 @@ func Test_hasRestrictedAnnotations(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := hasRestrictedAnnotations(tt.args.dep)
-			require.Equal(t, tt.want, got, "hasRestrictedAnnotations() mismatch")
+			got := HasRestrictedAnnotations(tt.args.dep)
+			require.Equal(t, tt.want, got, "HasRestrictedAnnotations() mismatch")
 		})
 	}
 }
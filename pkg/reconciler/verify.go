@@ -2,19 +2,29 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/kyma-project/keda-manager/api/v1alpha1"
 
 	"github.com/kyma-project/manager-toolkit/installation/base/resource"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func sFnVerify(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+func sFnVerify(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
 	var ready int
 	var replicaFailure int
 	var kedaVersion string
+	var components []v1alpha1.ComponentStatus
+	var notReady []string
 
 	for _, obj := range s.objs {
 		if !isDeployment(obj) {
@@ -35,9 +45,13 @@ func sFnVerify(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result
 			kedaVersion = deployment.GetLabels()["app.kubernetes.io/version"]
 		}
 
+		components = append(components, buildComponentStatus(deployment))
+
 		if resource.IsDeploymentReady(deployment) {
 			r.log.Debugf("successfully verified keda deployment %s/%s", obj.GetNamespace(), obj.GetName())
 			ready++
+		} else {
+			notReady = append(notReady, deployment.GetName())
 		}
 
 		if hasDeployReplicaFailure(deployment) {
@@ -45,6 +59,8 @@ func sFnVerify(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result
 		}
 	}
 
+	s.instance.Status.Components = components
+
 	if replicaFailure > 0 {
 		r.log.Debugf("%d deployments have ReplicaFailure condition", replicaFailure)
 		s.instance.UpdateStateReplicaFailure(
@@ -55,16 +71,22 @@ func sFnVerify(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result
 		return stopWithRequeueAfter(time.Second * 10)
 	}
 
-	if ready != 3 {
-		r.log.Debugf("%d deployments in ready state found ( 3 are expected ) ", ready)
+	// keda-operator, metrics server and admission webhooks are expected next to the external scalers
+	expected := 3 + len(s.instance.Spec.ExternalScalers)
+	if ready != expected {
+		r.log.Debugf("%d deployments in ready state found ( %d are expected ) ", ready, expected)
 		s.instance.UpdateStateProcessing(
 			v1alpha1.ConditionTypeInstalled,
 			v1alpha1.ConditionReasonVerification,
-			"verification in progress",
+			fmt.Sprintf("verification in progress, waiting for: %s", strings.Join(notReady, ", ")),
 		)
 		return stopWithRequeueAfter(time.Second * 10)
 	}
 
+	if next := verifyServices(ctx, r, s); next != nil {
+		return switchState(next)
+	}
+
 	// remove possible previous DeploymentFailure condition
 	s.instance.RemoveCondition(v1alpha1.ConditionTypeDeploymentFailure)
 
@@ -72,12 +94,185 @@ func sFnVerify(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result
 	s.instance.UpdateStateReady(
 		v1alpha1.ConditionTypeInstalled,
 		v1alpha1.ConditionReasonVerified,
-		"keda-operator and keda-operator-metrics-server ready",
+		fmt.Sprintf("%d components ready", len(components)),
 	)
 
-	// After keda is verified ready, handle the HTTP add-on.
-	// The addon state is independent and does not affect the overall keda state.
-	return switchState(sFnHandleAddon)
+	// After keda is verified ready, restore the custom resources removed by the cascade deletion
+	// and handle the HTTP add-on. The addon state is independent and does not affect the overall keda state.
+	return switchState(sFnRestoreCustomResources)
+}
+
+func buildComponentStatus(deployment appsv1.Deployment) v1alpha1.ComponentStatus {
+	status := v1alpha1.ComponentStatus{
+		Name:              deployment.GetName(),
+		DesiredReplicas:   ptr.Deref(deployment.Spec.Replicas, 1),
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		Version:           deployment.GetLabels()["app.kubernetes.io/version"],
+		LastError:         deploymentLastError(deployment),
+	}
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		status.Image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+	return status
+}
+
+// deploymentLastError returns the message of the first failing deployment condition
+func deploymentLastError(deployment appsv1.Deployment) string {
+	for _, condition := range deployment.Status.Conditions {
+		failing := (condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue) ||
+			(condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse) ||
+			(condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionFalse)
+		if failing {
+			return condition.Message
+		}
+	}
+	return ""
+}
+
+// verifyServices checks the external metrics APIService and the admission webhook endpoints
+// and returns the next state function if the Keda CR should stay in the Processing state
+func verifyServices(ctx context.Context, r *fsm, s *systemState) stateFn {
+	var waitingFor []string
+
+	apiAvailable, msg := verifyAPIServices(s.objs)
+	if apiAvailable {
+		s.instance.UpdateCondition(
+			v1alpha1.ConditionTypeMetricsAPIAvailable,
+			metav1.ConditionTrue,
+			v1alpha1.ConditionReasonAPIServiceAvailable,
+			msg,
+		)
+	} else {
+		waitingFor = append(waitingFor, "external metrics APIService")
+		s.instance.UpdateCondition(
+			v1alpha1.ConditionTypeMetricsAPIAvailable,
+			metav1.ConditionFalse,
+			v1alpha1.ConditionReasonAPIServiceUnavailable,
+			msg,
+		)
+	}
+
+	webhookReachable, msg, err := verifyWebhookEndpoints(ctx, r.Client, s.objs)
+	if err != nil {
+		s.instance.UpdateStateFromErr(
+			v1alpha1.ConditionTypeInstalled,
+			v1alpha1.ConditionReasonVerificationErr,
+			err,
+		)
+		return sFnUpdateStatus(nil, err)
+	}
+	if webhookReachable {
+		s.instance.UpdateCondition(
+			v1alpha1.ConditionTypeWebhookReachable,
+			metav1.ConditionTrue,
+			v1alpha1.ConditionReasonWebhookEndpointsReady,
+			msg,
+		)
+	} else {
+		waitingFor = append(waitingFor, "admission webhook endpoints")
+		s.instance.UpdateCondition(
+			v1alpha1.ConditionTypeWebhookReachable,
+			metav1.ConditionFalse,
+			v1alpha1.ConditionReasonWebhookEndpointsNotReady,
+			msg,
+		)
+	}
+
+	if len(waitingFor) == 0 {
+		return nil
+	}
+
+	r.log.Debugf("waiting for %s", strings.Join(waitingFor, ", "))
+	s.instance.UpdateStateProcessing(
+		v1alpha1.ConditionTypeInstalled,
+		v1alpha1.ConditionReasonVerification,
+		fmt.Sprintf("verification in progress, waiting for: %s", strings.Join(waitingFor, ", ")),
+	)
+	return sFnUpdateStatus(&ctrl.Result{RequeueAfter: time.Second * 10}, nil)
+}
+
+// verifyAPIServices checks the Available condition of the applied APIServices
+func verifyAPIServices(objs []unstructured.Unstructured) (bool, string) {
+	var names []string
+	for _, obj := range objs {
+		if !isAPIService(obj) {
+			continue
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		available := false
+		for _, raw := range conditions {
+			condition, ok := raw.(map[string]interface{})
+			if !ok || condition["type"] != "Available" {
+				continue
+			}
+			if condition["status"] == string(metav1.ConditionTrue) {
+				available = true
+				break
+			}
+			return false, fmt.Sprintf("APIService %s is not available: %v", obj.GetName(), condition["message"])
+		}
+		if !available {
+			return false, fmt.Sprintf("APIService %s has no Available condition", obj.GetName())
+		}
+		names = append(names, obj.GetName())
+	}
+	return true, fmt.Sprintf("APIService available: %s", strings.Join(names, ", "))
+}
+
+// verifyWebhookEndpoints checks that every service referenced by the applied webhook configurations has a ready endpoint
+func verifyWebhookEndpoints(ctx context.Context, c client.Client, objs []unstructured.Unstructured) (bool, string, error) {
+	var services []string
+	for _, obj := range objs {
+		if !isValidatingWebhookConfiguration(obj) {
+			continue
+		}
+
+		webhooks, _, _ := unstructured.NestedSlice(obj.Object, "webhooks")
+		for _, raw := range webhooks {
+			webhook, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, found, _ := unstructured.NestedString(webhook, "clientConfig", "service", "name")
+			if !found {
+				continue
+			}
+			namespace, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "namespace")
+
+			ready, err := hasReadyEndpoint(ctx, c, namespace, name)
+			if err != nil {
+				return false, "", err
+			}
+			if !ready {
+				return false, fmt.Sprintf("service %s/%s used by webhook %s has no ready endpoints", namespace, name, obj.GetName()), nil
+			}
+			services = append(services, fmt.Sprintf("%s/%s", namespace, name))
+		}
+	}
+	return true, fmt.Sprintf("webhook services have ready endpoints: %s", strings.Join(services, ", ")), nil
+}
+
+func hasReadyEndpoint(ctx context.Context, c client.Client, namespace, serviceName string) (bool, error) {
+	var slices discoveryv1.EndpointSliceList
+	err := c.List(ctx, &slices,
+		client.InNamespace(namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: serviceName},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// nil ready condition should be interpreted as ready
+			if ptr.Deref(endpoint.Conditions.Ready, true) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
 func hasDeployReplicaFailure(deployment appsv1.Deployment) bool {
@@ -0,0 +1,97 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/manager-toolkit/installation/base/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// sFnVerifyAddon checks the add-on components applied in this reconciliation
+// and reports the add-on as installed only when all of them are ready; the upgraded add-on
+// is rolled back when it does not become ready in time
+func sFnVerifyAddon(ctx context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	a := s.addon
+	notReady, err := a.NotReady(r.AddonObjs[a.Name()])
+	if err != nil {
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddon()
+	}
+
+	installed := installedAddon(&s.instance, a)
+	upgrade := installed.Upgrade
+	if len(notReady) > 0 && upgrade != nil && !upgrade.RolledBack && time.Since(upgrade.StartedAt.Time) > addonUpgradeTimeout {
+		return rollbackAddon(ctx, r, s, a, installed, notReady)
+	}
+
+	if len(notReady) > 0 {
+		r.log.Debugf("waiting for %s components: %s", a.Title(), strings.Join(notReady, ", "))
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonVerification,
+			fmt.Sprintf("%s v%s verification in progress, waiting for: %s", a.Title(), installed.Version, strings.Join(notReady, ", ")))
+		return stopAddonWithRequeueAfter(s, time.Second*10)
+	}
+
+	if upgrade != nil && upgrade.RolledBack {
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonUpgradeFailed,
+			fmt.Sprintf("%s upgrade to v%s failed, v%s is running in namespace %s; change the add-on configuration to retry",
+				a.Title(), upgrade.ToVersion, installed.Version, installed.Namespace))
+		return stopAddon()
+	}
+
+	if err := finishAddonUpgrade(ctx, r, s, a, &installed); err != nil {
+		r.log.With("err", err).Errorf("failed to remove old %s resources", a.Title())
+		setAddonCondition(s, a, metav1.ConditionFalse, v1alpha1.AddonReasonInstallErr, err.Error())
+		return stopAddonWithRequeueAfter(s, time.Second*10)
+	}
+
+	msg := fmt.Sprintf("%s v%s installed in namespace %s", a.Title(), installed.Version, installed.Namespace)
+	setAddonCondition(s, a, metav1.ConditionTrue, v1alpha1.AddonReasonInstalled, withDeprecationNotice(s, a, msg))
+	r.log.Info(msg)
+	return stopAddon()
+}
+
+// addonNotReadyComponents returns the add-on Deployments that are not ready and the CRDs that are not established,
+// the Deployments are reported by their names without the given prefix
+func addonNotReadyComponents(objs []unstructured.Unstructured, deploymentPrefix string) ([]string, error) {
+	var notReady []string
+	for _, obj := range objs {
+		switch {
+		case isDeployment(obj):
+			var deployment appsv1.Deployment
+			if err := fromUnstructured(obj.Object, &deployment); err != nil {
+				return nil, err
+			}
+			if resource.IsDeploymentReady(deployment) {
+				continue
+			}
+			component := strings.TrimPrefix(deployment.GetName(), deploymentPrefix)
+			if lastError := deploymentLastError(deployment); lastError != "" {
+				component = fmt.Sprintf("%s (%s)", component, lastError)
+			}
+			notReady = append(notReady, component)
+		case isCRD(obj):
+			if !hasTrueStatusCondition(obj, "Established") {
+				notReady = append(notReady, fmt.Sprintf("CRD %s", obj.GetName()))
+			}
+		}
+	}
+	return notReady, nil
+}
+
+func hasTrueStatusCondition(obj unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if ok && condition["type"] == conditionType {
+			return condition["status"] == string(metav1.ConditionTrue)
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func fixAddonCRD(established bool) unstructured.Unstructured {
+	status := "False"
+	if established {
+		status = "True"
+	}
+	crd := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Established", "status": status},
+			},
+		},
+	}}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("httpscaledobjects.http.keda.sh")
+	return crd
+}
+
+func Test_sFnVerifyAddon(t *testing.T) {
+	status := v1alpha1.Status{Addons: []v1alpha1.AddonStatus{
+		{Name: httpAddonName, Version: "0.15.0", Namespace: "kyma-system"},
+	}}
+
+	t.Run("all components ready", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {
+				fixAddonCRD(true),
+				fixVerifyDeployment(t, "keda-add-ons-http-interceptor", true),
+				fixVerifyDeployment(t, "keda-add-ons-http-external-scaler", true),
+			}}},
+		}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{Status: *status.DeepCopy()}}
+
+		next, result, err := sFnVerifyAddon(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Nil(t, s.addonsResult)
+		requireEqualFunc(t, sFnHandleAddons, next)
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.NotNil(t, condition)
+		require.Equal(t, metav1.ConditionTrue, condition.Status)
+		require.Equal(t, v1alpha1.ConditionReasonAddonInstalled, condition.Reason)
+		require.Equal(t, "HTTP add-on v0.15.0 installed in namespace kyma-system", condition.Message)
+	})
+
+	t.Run("components not ready", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			Cfg: Cfg{AddonObjs: map[string][]unstructured.Unstructured{httpAddonName: {
+				fixAddonCRD(false),
+				fixVerifyDeployment(t, "keda-add-ons-http-interceptor", false),
+				fixVerifyDeployment(t, "keda-add-ons-http-external-scaler", true),
+			}}},
+		}
+		s := &systemState{addon: httpAddon{}, instance: v1alpha1.Keda{Status: *status.DeepCopy()}}
+
+		next, result, err := sFnVerifyAddon(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Equal(t, &ctrl.Result{RequeueAfter: time.Second * 10}, s.addonsResult)
+		requireEqualFunc(t, sFnHandleAddons, next)
+
+		condition := meta.FindStatusCondition(s.instance.Status.Conditions, "AddonInstalled")
+		require.NotNil(t, condition)
+		require.Equal(t, metav1.ConditionFalse, condition.Status)
+		require.Equal(t, v1alpha1.ConditionReasonAddonVerification, condition.Reason)
+		require.Equal(t, "HTTP add-on v0.15.0 verification in progress, waiting for: "+
+			"CRD httpscaledobjects.http.keda.sh, interceptor (Deployment does not have minimum availability.)", condition.Message)
+	})
+}
@@ -0,0 +1,258 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fixVerifyDeployment(t *testing.T, name string, ready bool) unstructured.Unstructured {
+	deployment := appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kyma-system",
+			Labels:    map[string]string{"app.kubernetes.io/version": "2.20.1"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: name, Image: "keda:2.20.1"}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas:     1,
+			AvailableReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:    appsv1.DeploymentAvailable,
+					Status:  corev1.ConditionFalse,
+					Reason:  "MinimumReplicasUnavailable",
+					Message: "Deployment does not have minimum availability.",
+				},
+			},
+		},
+	}
+	if ready {
+		deployment.Status = appsv1.DeploymentStatus{
+			ReadyReplicas:     2,
+			AvailableReplicas: 2,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+			},
+		}
+	}
+
+	obj, err := toUnstructed(&deployment)
+	require.NoError(t, err)
+	return unstructured.Unstructured{Object: obj}
+}
+
+func Test_sFnVerify(t *testing.T) {
+	t.Run("report components and wait for not ready ones", func(t *testing.T) {
+		r := &fsm{log: zap.NewNop().Sugar()}
+		s := &systemState{
+			objs: []unstructured.Unstructured{
+				fixVerifyDeployment(t, operatorName, true),
+				fixVerifyDeployment(t, matricsServerName, false),
+				fixVerifyDeployment(t, admissionWebhooksName, true),
+			},
+		}
+
+		_, _, err := sFnVerify(context.Background(), r, s)
+		require.NoError(t, err)
+
+		require.Equal(t, v1alpha1.StateProcessing, s.instance.Status.State)
+		require.Len(t, s.instance.Status.Components, 3)
+		require.Equal(t, v1alpha1.ComponentStatus{
+			Name:              matricsServerName,
+			Image:             "keda:2.20.1",
+			DesiredReplicas:   2,
+			ReadyReplicas:     1,
+			AvailableReplicas: 1,
+			Version:           "2.20.1",
+			LastError:         "Deployment does not have minimum availability.",
+		}, s.instance.Status.Components[1])
+		require.Equal(t, "verification in progress, waiting for: keda-operator-metrics-apiserver",
+			s.instance.Status.Conditions[0].Message)
+	})
+
+	t.Run("all components ready", func(t *testing.T) {
+		r := &fsm{log: zap.NewNop().Sugar()}
+		s := &systemState{
+			objs: []unstructured.Unstructured{
+				fixVerifyDeployment(t, operatorName, true),
+				fixVerifyDeployment(t, matricsServerName, true),
+				fixVerifyDeployment(t, admissionWebhooksName, true),
+			},
+		}
+
+		next, _, err := sFnVerify(context.Background(), r, s)
+		require.NoError(t, err)
+		requireEqualFunc(t, sFnHandleAddon, next)
+
+		require.Equal(t, v1alpha1.StateReady, s.instance.Status.State)
+		require.Equal(t, "2.20.1", s.instance.Status.KedaVersion)
+		require.Len(t, s.instance.Status.Components, 3)
+		for _, component := range s.instance.Status.Components {
+			require.Empty(t, component.LastError)
+			require.Equal(t, int32(2), component.AvailableReplicas)
+		}
+	})
+}
+
+func fixAPIService(status string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "APIService",
+			"apiVersion": "apiregistration.k8s.io/v1",
+			"metadata": map[string]interface{}{
+				"name": "v1beta1.external.metrics.k8s.io",
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":    "Available",
+						"status":  status,
+						"message": "failing or missing response",
+					},
+				},
+			},
+		},
+	}
+}
+
+func fixWebhookConfiguration() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "ValidatingWebhookConfiguration",
+			"apiVersion": "admissionregistration.k8s.io/v1",
+			"metadata": map[string]interface{}{
+				"name": "keda-admission",
+			},
+			"webhooks": []interface{}{
+				map[string]interface{}{
+					"name": "vscaledobject.kb.io",
+					"clientConfig": map[string]interface{}{
+						"service": map[string]interface{}{
+							"name":      admissionWebhooksName,
+							"namespace": "kyma-system",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func fixEndpointSlice(ready bool) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      admissionWebhooksName + "-abcde",
+			Namespace: "kyma-system",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: admissionWebhooksName},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+			},
+		},
+	}
+}
+
+func Test_verifyAPIServices(t *testing.T) {
+	t.Run("available", func(t *testing.T) {
+		ok, _ := verifyAPIServices([]unstructured.Unstructured{fixAPIService("True")})
+		require.True(t, ok)
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		ok, msg := verifyAPIServices([]unstructured.Unstructured{fixAPIService("False")})
+		require.False(t, ok)
+		require.Equal(t, "APIService v1beta1.external.metrics.k8s.io is not available: failing or missing response", msg)
+	})
+
+	t.Run("no status", func(t *testing.T) {
+		apiService := fixAPIService("True")
+		unstructured.RemoveNestedField(apiService.Object, "status")
+
+		ok, _ := verifyAPIServices([]unstructured.Unstructured{apiService})
+		require.False(t, ok)
+	})
+}
+
+func Test_verifyWebhookEndpoints(t *testing.T) {
+	t.Run("ready endpoint", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(fixEndpointSlice(true)).Build()
+
+		ok, _, err := verifyWebhookEndpoints(context.Background(), c, []unstructured.Unstructured{fixWebhookConfiguration()})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("no ready endpoints", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithObjects(fixEndpointSlice(false)).Build()
+
+		ok, msg, err := verifyWebhookEndpoints(context.Background(), c, []unstructured.Unstructured{fixWebhookConfiguration()})
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, "service kyma-system/keda-admission-webhooks used by webhook keda-admission has no ready endpoints", msg)
+	})
+
+	t.Run("no endpoint slices", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+
+		ok, _, err := verifyWebhookEndpoints(context.Background(), c, []unstructured.Unstructured{fixWebhookConfiguration()})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func Test_sFnVerifyServices(t *testing.T) {
+	t.Run("stay in processing until metrics API is available", func(t *testing.T) {
+		r := &fsm{
+			log: zap.NewNop().Sugar(),
+			K8s: K8s{Client: fake.NewClientBuilder().WithObjects(fixEndpointSlice(true)).Build()},
+		}
+		s := &systemState{
+			objs: []unstructured.Unstructured{
+				fixVerifyDeployment(t, operatorName, true),
+				fixVerifyDeployment(t, matricsServerName, true),
+				fixVerifyDeployment(t, admissionWebhooksName, true),
+				fixAPIService("False"),
+				fixWebhookConfiguration(),
+			},
+		}
+
+		_, _, err := sFnVerify(context.Background(), r, s)
+		require.NoError(t, err)
+
+		require.Equal(t, v1alpha1.StateProcessing, s.instance.Status.State)
+		apiCondition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeMetricsAPIAvailable))
+		require.NotNil(t, apiCondition)
+		require.Equal(t, metav1.ConditionFalse, apiCondition.Status)
+		webhookCondition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeWebhookReachable))
+		require.NotNil(t, webhookCondition)
+		require.Equal(t, metav1.ConditionTrue, webhookCondition.Status)
+		installedCondition := meta.FindStatusCondition(s.instance.Status.Conditions, string(v1alpha1.ConditionTypeInstalled))
+		require.Equal(t, "verification in progress, waiting for: external metrics APIService", installedCondition.Message)
+	})
+}
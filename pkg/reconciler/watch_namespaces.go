@@ -0,0 +1,99 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	operatorRBACName = "keda-operator"
+	rbacAPIVersion   = "rbac.authorization.k8s.io/v1"
+)
+
+var (
+	isOperatorClusterRole predicate = func(u unstructured.Unstructured) bool {
+		return u.GetKind() == "ClusterRole" && u.GetName() == operatorRBACName
+	}
+	isOperatorClusterRoleBinding predicate = func(u unstructured.Unstructured) bool {
+		return u.GetKind() == "ClusterRoleBinding" && u.GetName() == operatorRBACName
+	}
+)
+
+// sFnUpdateWatchNamespaces swaps the cluster-wide keda-operator ClusterRole and ClusterRoleBinding
+// for a Role and RoleBinding in every watched namespace; the cluster-wide objects are pruned
+// with the rest of the inventory when the operator is restricted and the other way round
+func sFnUpdateWatchNamespaces(_ context.Context, r *fsm, s *systemState) (stateFn, *ctrl.Result, error) {
+	if len(s.instance.Spec.WatchNamespaces) == 0 {
+		return switchState(sFnUpdatePriorityClass)
+	}
+
+	objs, err := buildNamespacedOperatorRBAC(r.Objs, s.instance.Spec.WatchNamespaces)
+	if err != nil {
+		s.instance.UpdateStateFromErr(
+			v1alpha1.ConditionTypeInstalled,
+			v1alpha1.ConditionReasonWatchNamespacesErr,
+			err,
+		)
+		return stopWithErrorAndNoRequeue(err)
+	}
+
+	r.Objs = objs
+	return switchState(sFnUpdatePriorityClass)
+}
+
+// buildNamespacedOperatorRBAC returns a copy of the objects with the keda-operator ClusterRole and ClusterRoleBinding
+// replaced by a Role and RoleBinding per namespace; the objects are copied so the manifest shared between reconciliations is kept
+func buildNamespacedOperatorRBAC(objs []unstructured.Unstructured, namespaces []v1alpha1.WatchNamespace) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objs)+2*len(namespaces))
+	var roleFound, bindingFound bool
+	for _, obj := range objs {
+		switch {
+		case isOperatorClusterRole(obj):
+			roleFound = true
+			for _, ns := range namespaces {
+				result = append(result, namespacedRBACObj(obj, "Role", ns))
+			}
+		case isOperatorClusterRoleBinding(obj):
+			bindingFound = true
+			for _, ns := range namespaces {
+				binding := namespacedRBACObj(obj, "RoleBinding", ns)
+				if err := unstructured.SetNestedField(binding.Object, "Role", "roleRef", "kind"); err != nil {
+					return nil, err
+				}
+				result = append(result, binding)
+			}
+		default:
+			result = append(result, obj)
+		}
+	}
+
+	if !roleFound || !bindingFound {
+		return nil, fmt.Errorf("%w: %s ClusterRole or ClusterRoleBinding", ErrNotFound, operatorRBACName)
+	}
+	return result, nil
+}
+
+func namespacedRBACObj(obj unstructured.Unstructured, kind string, namespace v1alpha1.WatchNamespace) unstructured.Unstructured {
+	result := *obj.DeepCopy()
+	result.SetKind(kind)
+	result.SetNamespace(string(namespace))
+	return result
+}
+
+// fixWatchNamespaceObjects returns the objects identifying the keda-operator Role and RoleBinding in the namespace
+func fixWatchNamespaceObjects(namespace v1alpha1.WatchNamespace) []unstructured.Unstructured {
+	var objs []unstructured.Unstructured
+	for _, kind := range []string{"Role", "RoleBinding"} {
+		objs = append(objs, inventoryObj(v1alpha1.InventoryEntry{
+			APIVersion: rbacAPIVersion,
+			Kind:       kind,
+			Namespace:  string(namespace),
+			Name:       operatorRBACName,
+		}))
+	}
+	return objs
+}
@@ -0,0 +1,139 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func fixOperatorRBACObjs(t *testing.T) []unstructured.Unstructured {
+	var result []unstructured.Unstructured
+	for _, obj := range []interface{}{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacAPIVersion, Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: operatorRBACName},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"keda.sh"}, Resources: []string{"scaledobjects"}, Verbs: []string{"get"}},
+			},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacAPIVersion, Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: "keda-operator-minimal-cluster-role"},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacAPIVersion, Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: operatorRBACName},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: operatorRBACName},
+			Subjects: []rbacv1.Subject{
+				{Kind: "ServiceAccount", Name: operatorRBACName, Namespace: "kyma-system"},
+			},
+		},
+	} {
+		u, err := toUnstructed(obj)
+		require.NoError(t, err)
+		result = append(result, unstructured.Unstructured{Object: u})
+	}
+	return result
+}
+
+func Test_sFnUpdateWatchNamespaces(t *testing.T) {
+	t.Run("keep cluster-wide permissions", func(t *testing.T) {
+		objs := fixOperatorRBACObjs(t)
+		r := &fsm{log: zap.NewNop().Sugar(), Cfg: Cfg{Objs: objs}}
+		s := &systemState{}
+
+		next, result, err := sFnUpdateWatchNamespaces(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdatePriorityClass, next)
+		require.Equal(t, objs, r.Objs)
+	})
+
+	t.Run("swap cluster-wide permissions for namespaced ones", func(t *testing.T) {
+		objs := fixOperatorRBACObjs(t)
+		r := &fsm{log: zap.NewNop().Sugar(), Cfg: Cfg{Objs: objs}}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{WatchNamespaces: []v1alpha1.WatchNamespace{"team-a", "team-b"}},
+			},
+		}
+
+		next, result, err := sFnUpdateWatchNamespaces(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdatePriorityClass, next)
+
+		require.Len(t, r.Objs, 5)
+		require.Equal(t, "ClusterRole", r.Objs[2].GetKind())
+		require.Equal(t, "keda-operator-minimal-cluster-role", r.Objs[2].GetName())
+		for i, ns := range []string{"team-a", "team-b"} {
+			var role rbacv1.Role
+			require.NoError(t, fromUnstructured(r.Objs[i].Object, &role))
+			require.Equal(t, "Role", role.Kind)
+			require.Equal(t, ns, role.Namespace)
+			require.Equal(t, operatorRBACName, role.Name)
+			require.Equal(t, []string{"scaledobjects"}, role.Rules[0].Resources)
+
+			var binding rbacv1.RoleBinding
+			require.NoError(t, fromUnstructured(r.Objs[3+i].Object, &binding))
+			require.Equal(t, "RoleBinding", binding.Kind)
+			require.Equal(t, ns, binding.Namespace)
+			require.Equal(t, "Role", binding.RoleRef.Kind)
+			require.Equal(t, operatorRBACName, binding.RoleRef.Name)
+			require.Equal(t, "kyma-system", binding.Subjects[0].Namespace)
+		}
+
+		// the manifest objects are not modified
+		require.Equal(t, "ClusterRole", objs[0].GetKind())
+		require.Empty(t, objs[0].GetNamespace())
+	})
+
+	t.Run("missing ClusterRole", func(t *testing.T) {
+		r := &fsm{log: zap.NewNop().Sugar(), Cfg: Cfg{Objs: fixOperatorRBACObjs(t)[1:]}}
+		s := &systemState{
+			instance: v1alpha1.Keda{
+				Spec: v1alpha1.KedaSpec{WatchNamespaces: []v1alpha1.WatchNamespace{"team-a"}},
+			},
+		}
+
+		next, result, err := sFnUpdateWatchNamespaces(context.Background(), r, s)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnUpdateStatus(nil, nil), next)
+		require.Equal(t, v1alpha1.StateError, s.instance.Status.State)
+		require.Len(t, r.Objs, 2)
+	})
+}
+
+func Test_envVars(t *testing.T) {
+	t.Run("all namespaces", func(t *testing.T) {
+		k := &v1alpha1.Keda{Spec: v1alpha1.KedaSpec{Env: v1alpha1.EnvVars{{Name: "test", Value: "value"}}}}
+
+		require.Equal(t, &k.Spec.Env, envVars(k))
+	})
+
+	t.Run("watched namespaces", func(t *testing.T) {
+		k := &v1alpha1.Keda{Spec: v1alpha1.KedaSpec{WatchNamespaces: []v1alpha1.WatchNamespace{"team-a", "team-b"}}}
+
+		envs := envVars(k)
+		require.Equal(t, &v1alpha1.EnvVars{{Name: "WATCH_NAMESPACE", Value: "team-a,team-b"}}, envs)
+		require.Empty(t, k.Spec.Env)
+	})
+}
+
+func Test_fixWatchNamespaceObjects(t *testing.T) {
+	objs := fixWatchNamespaceObjects("team-a")
+
+	require.Len(t, objs, 2)
+	for i, kind := range []string{"Role", "RoleBinding"} {
+		require.Equal(t, kind, objs[i].GetKind())
+		require.Equal(t, "team-a", objs[i].GetNamespace())
+		require.Equal(t, operatorRBACName, objs[i].GetName())
+	}
+}
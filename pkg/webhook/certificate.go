@@ -0,0 +1,290 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	caCertKey = "ca.crt"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+	// certRenewBefore is the time before the expiration when the serving certificate is renewed
+	certRenewBefore = 30 * 24 * time.Hour
+	// certCheckInterval is the interval of the serving certificate expiration checks
+	certCheckInterval = 24 * time.Hour
+)
+
+// CertificateCfg configures the self-signed serving certificate of the keda-manager webhooks
+type CertificateCfg struct {
+	// SecretName is the Secret keeping the CA and the serving certificate
+	SecretName string
+	// Namespace of the Secret and the webhook Service
+	Namespace string
+	// ServiceName is the Service exposing the webhook server, it is used in the certificate DNS names
+	ServiceName string
+	// CertDir is the directory the webhook server reads the serving certificate from
+	CertDir string
+	// MutatingWebhookName and ValidatingWebhookName are the webhook configurations the CA is injected in
+	MutatingWebhookName   string
+	ValidatingWebhookName string
+}
+
+// CertificateManager keeps the webhook serving certificate valid; the certificate is stored in a Secret
+// so that all keda-manager replicas serve the same one, and the CA is injected in the webhook configurations
+type CertificateManager struct {
+	client.Client
+	Cfg CertificateCfg
+	Log *zap.SugaredLogger
+}
+
+// Start renews the serving certificate before it expires; it implements manager.Runnable
+func (m *CertificateManager) Start(ctx context.Context) error {
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.EnsureCertificate(ctx); err != nil {
+				m.Log.With("err", err).Error("failed to renew the webhook certificate")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, every replica writes the serving certificate of its own webhook server
+func (m *CertificateManager) NeedLeaderElection() bool {
+	return false
+}
+
+// EnsureCertificate generates the serving certificate if it is missing or expires soon,
+// writes it to the webhook server certificate directory and injects the CA in the webhook configurations
+func (m *CertificateManager) EnsureCertificate(ctx context.Context) error {
+	secret, err := m.ensureSecret(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot ensure webhook certificate secret: %w", err)
+	}
+
+	if err := writeCertificate(m.Cfg.CertDir, secret); err != nil {
+		return fmt.Errorf("cannot write webhook certificate: %w", err)
+	}
+
+	if err := m.injectCABundle(ctx, secret.Data[caCertKey]); err != nil {
+		return fmt.Errorf("cannot inject webhook CA bundle: %w", err)
+	}
+	return nil
+}
+
+func (m *CertificateManager) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := m.Get(ctx, client.ObjectKey{Namespace: m.Cfg.Namespace, Name: m.Cfg.SecretName}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.Cfg.SecretName,
+				Namespace: m.Cfg.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+		if secret.Data, err = generateCertificate(m.dnsNames(), time.Now()); err != nil {
+			return nil, err
+		}
+		err = m.Create(ctx, secret)
+		if apierrors.IsAlreadyExists(err) {
+			// created by another replica in the meantime
+			return m.getSecret(ctx)
+		}
+		return secret, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if isCertificateValid(secret.Data, m.dnsNames(), time.Now().Add(certRenewBefore)) {
+		return secret, nil
+	}
+
+	m.Log.Info("renewing the webhook certificate")
+	if secret.Data, err = generateCertificate(m.dnsNames(), time.Now()); err != nil {
+		return nil, err
+	}
+	err = m.Update(ctx, secret)
+	if apierrors.IsConflict(err) {
+		// renewed by another replica in the meantime
+		return m.getSecret(ctx)
+	}
+	return secret, err
+}
+
+func (m *CertificateManager) getSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := m.Get(ctx, client.ObjectKey{Namespace: m.Cfg.Namespace, Name: m.Cfg.SecretName}, secret)
+	return secret, err
+}
+
+func (m *CertificateManager) dnsNames() []string {
+	return []string{
+		m.Cfg.ServiceName,
+		fmt.Sprintf("%s.%s", m.Cfg.ServiceName, m.Cfg.Namespace),
+		fmt.Sprintf("%s.%s.svc", m.Cfg.ServiceName, m.Cfg.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", m.Cfg.ServiceName, m.Cfg.Namespace),
+	}
+}
+
+// injectCABundle sets the CA of all webhooks in the webhook configurations;
+// missing configurations are skipped, e.g. when keda-manager runs outside of the cluster
+func (m *CertificateManager) injectCABundle(ctx context.Context, caBundle []byte) error {
+	var mutating admissionregistrationv1.MutatingWebhookConfiguration
+	err := m.Get(ctx, client.ObjectKey{Name: m.Cfg.MutatingWebhookName}, &mutating)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err == nil {
+		changed := false
+		for i := range mutating.Webhooks {
+			changed = setCABundle(&mutating.Webhooks[i].ClientConfig, caBundle) || changed
+		}
+		if changed {
+			if err := m.Update(ctx, &mutating); err != nil {
+				return err
+			}
+		}
+	}
+
+	var validating admissionregistrationv1.ValidatingWebhookConfiguration
+	err = m.Get(ctx, client.ObjectKey{Name: m.Cfg.ValidatingWebhookName}, &validating)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err == nil {
+		changed := false
+		for i := range validating.Webhooks {
+			changed = setCABundle(&validating.Webhooks[i].ClientConfig, caBundle) || changed
+		}
+		if changed {
+			return m.Update(ctx, &validating)
+		}
+	}
+	return nil
+}
+
+func setCABundle(cfg *admissionregistrationv1.WebhookClientConfig, caBundle []byte) bool {
+	if bytes.Equal(cfg.CABundle, caBundle) {
+		return false
+	}
+	cfg.CABundle = caBundle
+	return true
+}
+
+// writeCertificate writes the serving certificate files if they differ from the stored ones;
+// the webhook server watches the files and reloads the certificate
+func writeCertificate(dir string, secret *corev1.Secret) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		path := filepath.Join(dir, key)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+		if err := os.WriteFile(path, secret.Data[key], 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isCertificateValid returns true if the serving certificate is valid for the DNS names at the given time
+func isCertificateValid(data map[string][]byte, dnsNames []string, at time.Time) bool {
+	if len(data[caCertKey]) == 0 || len(data[corev1.TLSPrivateKeyKey]) == 0 {
+		return false
+	}
+	block, _ := pem.Decode(data[corev1.TLSCertKey])
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || at.After(cert.NotAfter) {
+		return false
+	}
+	for _, name := range dnsNames {
+		if cert.VerifyHostname(name) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// generateCertificate returns a self-signed CA and a serving certificate signed by it
+func generateCertificate(dnsNames []string, now time.Time) (map[string][]byte, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: "keda-manager-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{
+		caCertKey:               pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fixCertificateManager(t *testing.T, objs ...client.Object) *CertificateManager {
+	return &CertificateManager{
+		Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+		Log:    zap.NewNop().Sugar(),
+		Cfg: CertificateCfg{
+			SecretName:            "webhook-cert",
+			Namespace:             "kyma-system",
+			ServiceName:           "webhook-service",
+			CertDir:               filepath.Join(t.TempDir(), "certs"),
+			MutatingWebhookName:   "mutating",
+			ValidatingWebhookName: "validating",
+		},
+	}
+}
+
+func Test_isCertificateValid(t *testing.T) {
+	now := time.Now()
+	dnsNames := []string{"webhook-service", "webhook-service.kyma-system.svc"}
+	data, err := generateCertificate(dnsNames, now)
+	require.NoError(t, err)
+
+	require.True(t, isCertificateValid(data, dnsNames, now))
+	require.False(t, isCertificateValid(data, dnsNames, now.Add(certValidity+time.Hour)))
+	require.False(t, isCertificateValid(data, []string{"other-service"}, now))
+	require.False(t, isCertificateValid(map[string][]byte{}, dnsNames, now))
+}
+
+func TestCertificateManager_EnsureCertificate(t *testing.T) {
+	t.Run("generate certificate and inject CA", func(t *testing.T) {
+		mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "mutating"},
+			Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mkeda.operator.kyma-project.io"}},
+		}
+		validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "validating"},
+			Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vkeda.operator.kyma-project.io"}},
+		}
+		m := fixCertificateManager(t, mutating, validating)
+
+		require.NoError(t, m.EnsureCertificate(context.Background()))
+
+		var secret corev1.Secret
+		require.NoError(t, m.Get(context.Background(), client.ObjectKey{Namespace: "kyma-system", Name: "webhook-cert"}, &secret))
+		require.True(t, isCertificateValid(secret.Data, m.dnsNames(), time.Now()))
+
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			data, err := os.ReadFile(filepath.Join(m.Cfg.CertDir, key))
+			require.NoError(t, err)
+			require.Equal(t, secret.Data[key], data)
+		}
+
+		require.NoError(t, m.Get(context.Background(), client.ObjectKeyFromObject(mutating), mutating))
+		require.Equal(t, secret.Data[caCertKey], mutating.Webhooks[0].ClientConfig.CABundle)
+		require.NoError(t, m.Get(context.Background(), client.ObjectKeyFromObject(validating), validating))
+		require.Equal(t, secret.Data[caCertKey], validating.Webhooks[0].ClientConfig.CABundle)
+	})
+
+	t.Run("reuse valid certificate", func(t *testing.T) {
+		m := fixCertificateManager(t)
+		data, err := generateCertificate(m.dnsNames(), time.Now())
+		require.NoError(t, err)
+		require.NoError(t, m.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: "kyma-system"},
+			Data:       data,
+		}))
+
+		require.NoError(t, m.EnsureCertificate(context.Background()))
+
+		cert, err := os.ReadFile(filepath.Join(m.Cfg.CertDir, corev1.TLSCertKey))
+		require.NoError(t, err)
+		require.Equal(t, data[corev1.TLSCertKey], cert)
+	})
+
+	t.Run("renew expiring certificate", func(t *testing.T) {
+		m := fixCertificateManager(t)
+		data, err := generateCertificate(m.dnsNames(), time.Now().Add(-certValidity+certRenewBefore/2))
+		require.NoError(t, err)
+		require.NoError(t, m.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: "kyma-system"},
+			Data:       data,
+		}))
+
+		require.NoError(t, m.EnsureCertificate(context.Background()))
+
+		var secret corev1.Secret
+		require.NoError(t, m.Get(context.Background(), client.ObjectKey{Namespace: "kyma-system", Name: "webhook-cert"}, &secret))
+		require.NotEqual(t, data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey])
+		require.True(t, isCertificateValid(secret.Data, m.dnsNames(), time.Now().Add(certRenewBefore)))
+	})
+}
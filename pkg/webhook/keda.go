@@ -0,0 +1,188 @@
+// Package webhook implements the admission webhooks of the Keda CR.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/reconciler"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-operator-kyma-project-io-v1alpha1-keda,mutating=true,failurePolicy=ignore,sideEffects=None,groups=operator.kyma-project.io,resources=kedas,verbs=create,versions=v1alpha1,name=mkeda.operator.kyma-project.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-operator-kyma-project-io-v1alpha1-keda,mutating=false,failurePolicy=ignore,sideEffects=None,groups=operator.kyma-project.io,resources=kedas,verbs=create;update,versions=v1alpha1,name=vkeda.operator.kyma-project.io,admissionReviewVersions=v1
+
+// watchNamespaceEnv is managed by keda-manager and must not be overridden in spec.env
+const watchNamespaceEnv = "WATCH_NAMESPACE"
+
+// KedaWebhook validates and defaults the Keda CR before it is stored. The webhook failure policy is Ignore, so the CRs
+// stored while keda-manager is down are not validated: the reconciler still rejects the restricted annotations and
+// a second served instance, and a WATCH_NAMESPACE variable in spec.env is applied like before the webhook existed
+type KedaWebhook struct {
+	client.Reader
+	// Defaults holds the logging and resources of the KEDA components in the KEDA manifest
+	Defaults v1alpha1.KedaSpec
+}
+
+// SetupKedaWebhookWithManager registers the Keda CR webhooks in the manager webhook server,
+// the created Keda CRs are defaulted with the values of the KEDA manifest objects
+func SetupKedaWebhookWithManager(mgr ctrl.Manager, objs []unstructured.Unstructured) error {
+	defaults, err := reconciler.DefaultSpec(objs)
+	if err != nil {
+		return fmt.Errorf("failed to read the Keda CR defaults from the KEDA manifest: %w", err)
+	}
+	w := &KedaWebhook{Reader: mgr.GetAPIReader(), Defaults: defaults}
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha1.Keda{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+var _ admission.Defaulter[*v1alpha1.Keda] = &KedaWebhook{}
+var _ admission.Validator[*v1alpha1.Keda] = &KedaWebhook{}
+
+// Default sets the logging and resources of the KEDA components that are not configured to the values
+// of the KEDA manifest. The reconciler keeps the same values for the fields that are not set, so only
+// created CRs are defaulted and the CRs stored while keda-manager is down or before the webhook existed behave the same
+func (w *KedaWebhook) Default(ctx context.Context, keda *v1alpha1.Keda) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+	defaultLogging(&keda.Spec, w.Defaults.Logging)
+	defaultResources(&keda.Spec, w.Defaults.Resources)
+	return nil
+}
+
+// ValidateCreate rejects invalid specs and a second Keda CR when one is already served
+func (w *KedaWebhook) ValidateCreate(ctx context.Context, keda *v1alpha1.Keda) (admission.Warnings, error) {
+	servedKeda, err := reconciler.FindServedKeda(ctx, w.Reader)
+	if err != nil {
+		return nil, apierrors.NewInternalError(fmt.Errorf("cannot list Keda CRs: %w", err))
+	}
+	if servedKeda != nil {
+		return nil, apierrors.NewForbidden(v1alpha1.GroupVersion.WithResource("kedas").GroupResource(), keda.GetName(),
+			fmt.Errorf("only one instance of Keda is allowed (current served instance: %s/%s)",
+				servedKeda.GetNamespace(), servedKeda.GetName()))
+	}
+	return nil, validate(keda, nil)
+}
+
+// ValidateUpdate validates the spec when it changes; metadata updates, e.g. finalizer removal, are always allowed
+func (w *KedaWebhook) ValidateUpdate(_ context.Context, oldKeda, newKeda *v1alpha1.Keda) (admission.Warnings, error) {
+	if !newKeda.GetDeletionTimestamp().IsZero() || reflect.DeepEqual(oldKeda.Spec, newKeda.Spec) {
+		return nil, nil
+	}
+	return nil, validate(newKeda, oldKeda)
+}
+
+// ValidateDelete allows the deletion, the deletion policy is enforced by the reconciler
+func (w *KedaWebhook) ValidateDelete(_ context.Context, _ *v1alpha1.Keda) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate rejects the invalid spec; the WATCH_NAMESPACE variable set in spec.env before the webhook existed
+// is kept when the old CR is given and the variable is not changed
+func validate(keda, oldKeda *v1alpha1.Keda) error {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if reconciler.HasRestrictedAnnotations(*keda) {
+		errs = append(errs, field.Forbidden(specPath.Child("podAnnotations"),
+			fmt.Sprintf("annotations %s, %s and %s are reserved for the Kyma bootstrapper",
+				v1alpha1.KymaBootstraperAddImagePullSecretMutation,
+				v1alpha1.KymaBootstraperRegistryUrlMutation,
+				v1alpha1.KymaBootstrapperSetFipsMode)))
+	}
+
+	for i, env := range keda.Spec.Env {
+		if env.Name == watchNamespaceEnv && !hasEnv(oldKeda, env) {
+			errs = append(errs, field.Forbidden(specPath.Child("env").Index(i).Child("name"),
+				fmt.Sprintf("%s is managed by keda-manager, use spec.watchNamespaces instead", watchNamespaceEnv)))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("Keda").GroupKind(), keda.GetName(), errs)
+}
+
+func defaultLogging(spec *v1alpha1.KedaSpec, defaults *v1alpha1.LoggingCfg) {
+	if defaults == nil {
+		return
+	}
+	if spec.Logging == nil {
+		spec.Logging = &v1alpha1.LoggingCfg{}
+	}
+	for _, cfg := range []struct {
+		value    **v1alpha1.LoggingCommonCfg
+		defaults *v1alpha1.LoggingCommonCfg
+	}{
+		{&spec.Logging.Operator, defaults.Operator},
+		{&spec.Logging.MetricsServer, defaults.MetricsServer},
+		{&spec.Logging.AdmissionWebhook, defaults.AdmissionWebhook},
+	} {
+		if cfg.defaults == nil {
+			continue
+		}
+		if *cfg.value == nil {
+			*cfg.value = &v1alpha1.LoggingCommonCfg{}
+		}
+		value := *cfg.value
+		if value.Level == nil {
+			value.Level = cfg.defaults.Level
+		}
+		if value.Format == nil {
+			value.Format = cfg.defaults.Format
+		}
+		if value.TimeEncoding == nil {
+			value.TimeEncoding = cfg.defaults.TimeEncoding
+		}
+	}
+}
+
+func defaultResources(spec *v1alpha1.KedaSpec, defaults *v1alpha1.Resources) {
+	if defaults == nil {
+		return
+	}
+	if spec.Resources == nil {
+		spec.Resources = &v1alpha1.Resources{}
+	}
+	for _, resources := range []struct {
+		value    **corev1.ResourceRequirements
+		defaults *corev1.ResourceRequirements
+	}{
+		{&spec.Resources.Operator, defaults.Operator},
+		{&spec.Resources.MetricsServer, defaults.MetricsServer},
+		{&spec.Resources.AdmissionWebhook, defaults.AdmissionWebhook},
+	} {
+		if *resources.value == nil && resources.defaults != nil {
+			*resources.value = resources.defaults.DeepCopy()
+		}
+	}
+}
+
+func hasEnv(keda *v1alpha1.Keda, env corev1.EnvVar) bool {
+	if keda == nil {
+		return false
+	}
+	for _, oldEnv := range keda.Spec.Env {
+		if reflect.DeepEqual(oldEnv, env) {
+			return true
+		}
+	}
+	return false
+}
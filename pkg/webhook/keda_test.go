@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/keda-manager/api/v1alpha1"
+	"github.com/kyma-project/keda-manager/pkg/reconciler"
+	"github.com/kyma-project/keda-manager/pkg/resources"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func fixKedaWebhook(t *testing.T, objs ...*v1alpha1.Keda) *KedaWebhook {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	return &KedaWebhook{Reader: builder.Build()}
+}
+
+func fixKeda(name string, served string) *v1alpha1.Keda {
+	return &v1alpha1.Keda{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kyma-system"},
+		Status:     v1alpha1.Status{Served: served},
+	}
+}
+
+func TestKedaWebhook_ValidateCreate(t *testing.T) {
+	t.Run("first instance", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+
+		_, err := w.ValidateCreate(context.Background(), fixKeda("default", ""))
+		require.NoError(t, err)
+	})
+
+	t.Run("not served instance exists", func(t *testing.T) {
+		w := fixKedaWebhook(t, fixKeda("other", v1alpha1.ServedFalse))
+
+		_, err := w.ValidateCreate(context.Background(), fixKeda("default", ""))
+		require.NoError(t, err)
+	})
+
+	t.Run("served instance exists", func(t *testing.T) {
+		w := fixKedaWebhook(t, fixKeda("default", v1alpha1.ServedTrue))
+
+		_, err := w.ValidateCreate(context.Background(), fixKeda("second", ""))
+		require.True(t, apierrors.IsForbidden(err))
+		require.ErrorContains(t, err, "current served instance: kyma-system/default")
+	})
+
+	t.Run("restricted annotations", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+		keda := fixKeda("default", "")
+		keda.Spec.PodAnnotations = &v1alpha1.PodAnnotations{
+			Operator: map[string]string{v1alpha1.KymaBootstrapperSetFipsMode: "true"},
+		}
+
+		_, err := w.ValidateCreate(context.Background(), keda)
+		require.True(t, apierrors.IsInvalid(err))
+		require.ErrorContains(t, err, "spec.podAnnotations")
+	})
+
+	t.Run("WATCH_NAMESPACE override", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+		keda := fixKeda("default", "")
+		keda.Spec.Env = v1alpha1.EnvVars{
+			{Name: "KEDA_HTTP_MIN_TLS_VERSION", Value: "TLS13"},
+			{Name: "WATCH_NAMESPACE", Value: "my-namespace"},
+		}
+
+		_, err := w.ValidateCreate(context.Background(), keda)
+		require.True(t, apierrors.IsInvalid(err))
+		require.ErrorContains(t, err, "spec.env[1].name")
+	})
+}
+
+func TestKedaWebhook_ValidateUpdate(t *testing.T) {
+	invalid := fixKeda("default", v1alpha1.ServedTrue)
+	invalid.Spec.PodAnnotations = &v1alpha1.PodAnnotations{
+		MetricsServer: map[string]string{v1alpha1.KymaBootstraperRegistryUrlMutation: "true"},
+	}
+
+	t.Run("invalid spec change", func(t *testing.T) {
+		w := fixKedaWebhook(t, fixKeda("default", v1alpha1.ServedTrue))
+
+		_, err := w.ValidateUpdate(context.Background(), fixKeda("default", v1alpha1.ServedTrue), invalid)
+		require.True(t, apierrors.IsInvalid(err))
+	})
+
+	t.Run("metadata change of invalid instance", func(t *testing.T) {
+		w := fixKedaWebhook(t, invalid)
+		updated := invalid.DeepCopy()
+		updated.Finalizers = nil
+
+		_, err := w.ValidateUpdate(context.Background(), invalid, updated)
+		require.NoError(t, err)
+	})
+
+	t.Run("deleted instance", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+		deleted := invalid.DeepCopy()
+		deleted.DeletionTimestamp = ptr.To(metav1.Now())
+
+		_, err := w.ValidateUpdate(context.Background(), fixKeda("default", v1alpha1.ServedTrue), deleted)
+		require.NoError(t, err)
+	})
+
+	t.Run("unchanged WATCH_NAMESPACE set before the webhook existed", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+		old := fixKeda("default", v1alpha1.ServedTrue)
+		old.Spec.Env = v1alpha1.EnvVars{{Name: "WATCH_NAMESPACE", Value: "my-namespace"}}
+		updated := old.DeepCopy()
+		updated.Spec.DeletionPolicy = v1alpha1.DeletionPolicyCascade
+
+		_, err := w.ValidateUpdate(context.Background(), old, updated)
+		require.NoError(t, err)
+	})
+
+	t.Run("changed WATCH_NAMESPACE", func(t *testing.T) {
+		w := fixKedaWebhook(t)
+		old := fixKeda("default", v1alpha1.ServedTrue)
+		old.Spec.Env = v1alpha1.EnvVars{{Name: "WATCH_NAMESPACE", Value: "my-namespace"}}
+		updated := old.DeepCopy()
+		updated.Spec.Env[0].Value = "other-namespace"
+
+		_, err := w.ValidateUpdate(context.Background(), old, updated)
+		require.True(t, apierrors.IsInvalid(err))
+		require.ErrorContains(t, err, "spec.env[0].name")
+	})
+
+	t.Run("second served instance is not checked", func(t *testing.T) {
+		w := fixKedaWebhook(t, fixKeda("default", v1alpha1.ServedTrue))
+		updated := fixKeda("second", v1alpha1.ServedFalse)
+		updated.Spec.DeletionPolicy = v1alpha1.DeletionPolicyCascade
+
+		_, err := w.ValidateUpdate(context.Background(), fixKeda("second", v1alpha1.ServedFalse), updated)
+		require.NoError(t, err)
+	})
+}
+
+func fixDefaultingWebhook(t *testing.T) *KedaWebhook {
+	objs, err := resources.LoadFromPaths("../../keda.yaml")
+	require.NoError(t, err)
+	defaults, err := reconciler.DefaultSpec(objs)
+	require.NoError(t, err)
+	return &KedaWebhook{Defaults: defaults}
+}
+
+func fixAdmissionContext(operation admissionv1.Operation) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Operation: operation},
+	})
+}
+
+func TestKedaWebhook_Default(t *testing.T) {
+	w := fixDefaultingWebhook(t)
+	manifestResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1000Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("100Mi"),
+		},
+	}
+
+	t.Run("empty spec", func(t *testing.T) {
+		keda := fixKeda("default", "")
+
+		require.NoError(t, w.Default(fixAdmissionContext(admissionv1.Create), keda))
+
+		for _, cfg := range []*v1alpha1.LoggingCommonCfg{
+			keda.Spec.Logging.Operator,
+			keda.Spec.Logging.MetricsServer,
+			keda.Spec.Logging.AdmissionWebhook,
+		} {
+			require.Equal(t, v1alpha1.CommonLogLevelInfo, *cfg.Level)
+			require.Equal(t, v1alpha1.LogFormatJSON, *cfg.Format)
+			require.Equal(t, v1alpha1.TimeEncodingRFC3339, *cfg.TimeEncoding)
+		}
+		for _, resources := range []*corev1.ResourceRequirements{
+			keda.Spec.Resources.Operator,
+			keda.Spec.Resources.MetricsServer,
+			keda.Spec.Resources.AdmissionWebhook,
+		} {
+			require.True(t, resources.Limits.Cpu().Equal(*manifestResources.Limits.Cpu()))
+			require.True(t, resources.Limits.Memory().Equal(*manifestResources.Limits.Memory()))
+			require.True(t, resources.Requests.Cpu().Equal(*manifestResources.Requests.Cpu()))
+			require.True(t, resources.Requests.Memory().Equal(*manifestResources.Requests.Memory()))
+		}
+	})
+
+	t.Run("keep configured values", func(t *testing.T) {
+		keda := fixKeda("default", "")
+		operatorResources := &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		keda.Spec.Logging = &v1alpha1.LoggingCfg{
+			Operator: &v1alpha1.LoggingCommonCfg{Level: ptr.To(v1alpha1.CommonLogLevelDebug)},
+		}
+		keda.Spec.Resources = &v1alpha1.Resources{Operator: operatorResources}
+
+		require.NoError(t, w.Default(fixAdmissionContext(admissionv1.Create), keda))
+
+		require.Equal(t, v1alpha1.CommonLogLevelDebug, *keda.Spec.Logging.Operator.Level)
+		require.Equal(t, v1alpha1.LogFormatJSON, *keda.Spec.Logging.Operator.Format)
+		require.Equal(t, v1alpha1.CommonLogLevelInfo, *keda.Spec.Logging.MetricsServer.Level)
+		require.Equal(t, operatorResources, keda.Spec.Resources.Operator)
+		require.Equal(t, w.Defaults.Resources.MetricsServer, keda.Spec.Resources.MetricsServer)
+	})
+
+	t.Run("updated instance is not defaulted", func(t *testing.T) {
+		keda := fixKeda("default", "")
+
+		require.NoError(t, w.Default(fixAdmissionContext(admissionv1.Update), keda))
+		require.Nil(t, keda.Spec.Logging)
+		require.Nil(t, keda.Spec.Resources)
+	})
+
+	t.Run("no admission request", func(t *testing.T) {
+		require.Error(t, w.Default(context.Background(), fixKeda("default", "")))
+	})
+}